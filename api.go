@@ -2,7 +2,13 @@ package glice
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
@@ -10,6 +16,10 @@ import (
 	"github.com/fatih/color"
 	"github.com/gocolly/colly"
 	"github.com/google/go-github/github"
+	"github.com/shurcooL/githubv4"
+	"github.com/xanzy/go-gitlab"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
 	"golang.org/x/oauth2"
 )
 
@@ -19,14 +29,22 @@ type licenseFormat struct {
 }
 
 var licenseCol = map[string]licenseFormat{
-	"other":      {name: "Other", color: color.FgBlue},
-	"mit":        {name: "MIT", color: color.FgGreen},
-	"lgpl-3.0":   {name: "LGPL-3.0", color: color.FgCyan},
-	"mpl-2.0":    {name: "MPL-2.0", color: color.FgHiBlue},
-	"agpl-3.0":   {name: "AGPL-3.0", color: color.FgHiCyan},
-	"unlicense":  {name: "Unlicense", color: color.FgHiRed},
-	"apache-2.0": {name: "Apache-2.0", color: color.FgHiGreen},
-	"gpl-3.0":    {name: "GPL-3.0", color: color.FgHiMagenta},
+	"other":        {name: "Other", color: color.FgBlue},
+	"mit":          {name: "MIT", color: color.FgGreen},
+	"lgpl-3.0":     {name: "LGPL-3.0-only", color: color.FgCyan},
+	"mpl-2.0":      {name: "MPL-2.0", color: color.FgHiBlue},
+	"agpl-3.0":     {name: "AGPL-3.0-only", color: color.FgHiCyan},
+	"unlicense":    {name: "Unlicense", color: color.FgHiRed},
+	"apache-2.0":   {name: "Apache-2.0", color: color.FgHiGreen},
+	"gpl-3.0":      {name: "GPL-3.0-only", color: color.FgHiMagenta},
+	"isc":          {name: "ISC", color: color.FgGreen},
+	"eupl-1.2":     {name: "EUPL-1.2", color: color.FgMagenta},
+	"osl-3.0":      {name: "OSL-3.0", color: color.FgHiCyan},
+	"cddl-1.0":     {name: "CDDL-1.0", color: color.FgCyan},
+	"artistic-1.0": {name: "Artistic-1.0", color: color.FgRed},
+	"ms-pl":        {name: "MS-PL", color: color.FgHiGreen},
+	"ms-rl":        {name: "MS-RL", color: color.FgHiBlue},
+	"wtfpl":        {name: "WTFPL", color: color.FgHiWhite},
 }
 var licenseColMap = map[string]color.Attribute{
 	"mit":          color.FgGreen,
@@ -44,52 +62,475 @@ var licenseColMap = map[string]color.Attribute{
 	"cc0-1.0":      color.FgHiWhite,
 	"unlicense":    color.FgHiRed,
 	"agpl-3.0":     color.FgHiCyan,
+	"isc":          color.FgGreen,
+	"eupl-1.2":     color.FgMagenta,
+	"osl-3.0":      color.FgHiCyan,
+	"cddl-1.0":     color.FgCyan,
+	"artistic-1.0": color.FgRed,
+	"ms-pl":        color.FgHiGreen,
+	"ms-rl":        color.FgHiBlue,
+	"wtfpl":        color.FgHiWhite,
 	"other":        color.FgBlue,
 }
 
+// License categories returned by LicenseCategory
+const (
+	CategoryPermissive      = "permissive"
+	CategoryWeakCopyleft    = "weak-copyleft"
+	CategoryStrongCopyleft  = "strong-copyleft"
+	CategoryNetworkCopyleft = "network-copyleft"
+	CategoryPublicDomain    = "public-domain"
+	CategoryUnknown         = "unknown"
+)
+
+var licenseCategories = map[string]string{
+	"mit":          CategoryPermissive,
+	"apache-2.0":   CategoryPermissive,
+	"bsd-2-clause": CategoryPermissive,
+	"bsd-3-clause": CategoryPermissive,
+	"bsl-1.0":      CategoryPermissive,
+	"artistic-2.0": CategoryPermissive,
+	"isc":          CategoryPermissive,
+	"zlib":         CategoryPermissive,
+	"artistic-1.0": CategoryPermissive,
+	"ms-pl":        CategoryPermissive,
+	"wtfpl":        CategoryPermissive,
+	"lgpl-2.1":     CategoryWeakCopyleft,
+	"lgpl-3.0":     CategoryWeakCopyleft,
+	"mpl-2.0":      CategoryWeakCopyleft,
+	"epl-2.0":      CategoryWeakCopyleft,
+	"cddl-1.0":     CategoryWeakCopyleft,
+	"ms-rl":        CategoryWeakCopyleft,
+	"gpl-2.0":      CategoryStrongCopyleft,
+	"gpl-3.0":      CategoryStrongCopyleft,
+	"eupl-1.2":     CategoryStrongCopyleft,
+	"agpl-3.0":     CategoryNetworkCopyleft,
+	// OSL-3.0 treats deploying modified work as a service as distributing it
+	"osl-3.0":   CategoryNetworkCopyleft,
+	"unlicense": CategoryPublicDomain,
+	"cc0-1.0":   CategoryPublicDomain,
+}
+
+// LicenseCategory classifies license by its SPDX identifier (case-insensitive)
+func LicenseCategory(spdxID string) string {
+	if cat, ok := licenseCategories[licenseFamily(spdxID)]; ok {
+		return cat
+	}
+	return CategoryUnknown
+}
+
 func getLicenseColor(license string) color.Attribute {
 	license = strings.ToLower(license)
+	if f, ok := licenseCol[license]; ok {
+		return f.color
+	}
 	if color, ok := licenseColMap[license]; ok {
 		return color
 	}
+	// -only and -or-later licenses are colored as their family
+	if color, ok := licenseColMap[licenseFamily(license)]; ok {
+		return color
+	}
 	return color.FgYellow // 默认颜色
 }
 
+// colorize returns s in color attr, or plain s when colors are disabled
+func colorize(enabled bool, attr color.Attribute, s string) string {
+	if !enabled {
+		return s
+	}
+	cl := color.New(attr)
+	cl.EnableColor()
+	return cl.Sprint(s)
+}
+
 // Repository holds information about the repository
 type Repository struct {
-	Name      string `json:"name,omitempty"`
-	Shortname string `json:"-"`
-	URL       string `json:"url,omitempty"`
-	Host      string `json:"host,omitempty"`
-	Author    string `json:"author,omitempty"`
-	Project   string `json:"project,omitempty"`
-	Text      string `json:"-"`
-	License   string `json:"license"`
-	Version   string `json:"Version"`
-}
-
-func newGitClient(c context.Context, keys map[string]string, star bool) *gitClient {
-	var tc *http.Client
+	Name      string `json:"name,omitempty" yaml:"name,omitempty" xml:"name,omitempty"`
+	Shortname string `json:"-" yaml:"-" xml:"-"`
+	URL       string `json:"url,omitempty" yaml:"url,omitempty" xml:"url,omitempty"`
+	Host      string `json:"host,omitempty" yaml:"-" xml:"host,omitempty"`
+	// HostURL is hostname of Gitea or Forgejo instance when Host is gitea
+	HostURL string `json:"host_url,omitempty" yaml:"-" xml:"host_url,omitempty"`
+	Author  string `json:"author,omitempty" yaml:"author,omitempty" xml:"author,omitempty"`
+	Project string `json:"project,omitempty" yaml:"-" xml:"project,omitempty"`
+	// IsStdlib is set for standard library pseudo-modules and golang.org/x/ modules, which are part of Go project
+	IsStdlib bool   `json:"is_stdlib,omitempty" yaml:"is_stdlib,omitempty" xml:"is_stdlib,omitempty"`
+	Text     string `json:"-" yaml:"-" xml:"-"`
+	License  string `json:"license" yaml:"license" xml:"license,omitempty"`
+	// LicenseURL links to license file of the repository, or to license page on pkg.go.dev
+	LicenseURL string `json:"license_url,omitempty" yaml:"license_url,omitempty" xml:"license_url,omitempty"`
+	// LicenseHash is SHA-256 hex digest of decoded license text, which tells whether license text of
+	// the dependency changed since previous run, e.g. when it was relicensed
+	LicenseHash string `json:"license_hash,omitempty" yaml:"license_hash,omitempty" xml:"license_hash,omitempty"`
+	// AlternateLicenses are other licenses dual licensed dependency can be used under
+	AlternateLicenses []string `json:"alternate_licenses,omitempty" yaml:"alternate_licenses,omitempty" xml:"alternate_license,omitempty"`
+	// AdditionalLicenses are other licenses found in license text that doesn't state a choice between
+	// them, e.g. of bundled third-party code. They apply together with License rather than instead of it.
+	AdditionalLicenses []string `json:"additional_licenses,omitempty" yaml:"additional_licenses,omitempty" xml:"additional_license,omitempty"`
+	// LicenseSPDX is SPDX identifier of License, empty when license is not recognized
+	LicenseSPDX string `json:"license_spdx" yaml:"license_spdx,omitempty" xml:"license_spdx,omitempty"`
+	Category    string `json:"category,omitempty" yaml:"category,omitempty" xml:"category,omitempty"`
+	// RiskScore tells how restrictive License is, see LicenseRiskScore
+	RiskScore int    `json:"risk_score" yaml:"risk_score,omitempty" xml:"risk_score,omitempty"`
+	Version   string `json:"Version" yaml:"version" xml:"version,omitempty"`
+	// LatestVersion is the latest version shown by pkg.go.dev, set for dependencies scraped from it
+	LatestVersion string `json:"latest_version,omitempty" yaml:"latest_version,omitempty" xml:"latest_version,omitempty"`
+	// IsOutdated is set when LatestVersion is newer than Version
+	IsOutdated bool `json:"outdated,omitempty" yaml:"outdated,omitempty" xml:"outdated,omitempty"`
+	// CommitSHA is git SHA license of GitHub repository was read from. GitHub API reports SHA of
+	// the license file object, which identifies its exact content.
+	CommitSHA string `json:"commit_sha,omitempty" yaml:"commit_sha,omitempty" xml:"commit_sha,omitempty"`
+	// PURL is package URL of the dependency, used to identify it in SBOMs
+	PURL string `json:"purl,omitempty" yaml:"purl,omitempty" xml:"purl,omitempty"`
+	// IsArchived, ArchivedAt and PushedAt are set for GitHub repositories when abandoned projects are
+	// checked, see WithAbandonedThreshold
+	IsArchived bool       `json:"archived,omitempty" yaml:"archived,omitempty" xml:"archived,omitempty"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty" yaml:"archived_at,omitempty" xml:"archived_at,omitempty"`
+	PushedAt   *time.Time `json:"pushed_at,omitempty" yaml:"pushed_at,omitempty" xml:"pushed_at,omitempty"`
+	// Stars is number of stargazers of GitHub repository, set when repository details are fetched
+	// anyway: when starring repositories, checking abandoned ones or using GraphQL API
+	Stars int `json:"stars,omitempty" yaml:"stars,omitempty" xml:"stars,omitempty"`
+	// Vulnerabilities are OSV IDs of vulnerabilities affecting Version, set by Client.CheckVulnerabilities
+	Vulnerabilities []string `json:"vulnerabilities,omitempty" yaml:"vulnerabilities,omitempty" xml:"vulnerability,omitempty"`
+	// LicenseFiles are all license and notice files in repository root, set for GitHub
+	// repositories when fetched with WithFetchAllLicenseFiles
+	LicenseFiles []LicenseFile `json:"license_files,omitempty" yaml:"license_files,omitempty" xml:"license_file,omitempty"`
+}
+
+// LicenseFile is a license or notice file of a repository, e.g. LICENSE, NOTICE or COPYING
+type LicenseFile struct {
+	Name string `json:"name" yaml:"name" xml:"name"`
+	// Text is plain text of the file
+	Text string `json:"text,omitempty" yaml:"text,omitempty" xml:"text,omitempty"`
+	// SPDX is identifier of license detected in Text, empty for notices and unrecognized licenses
+	SPDX string `json:"spdx,omitempty" yaml:"spdx,omitempty" xml:"spdx,omitempty"`
+}
+
+// licenseFilePrefixes are prefixes of names of files listed in LicenseFiles
+var licenseFilePrefixes = []string{"license", "licence", "notice", "copying"}
+
+func isLicenseFile(name string) bool {
+	name = strings.ToLower(name)
+	for _, p := range licenseFilePrefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// LicenseText returns plain license text of the repository. Text fetched from GitHub, GitLab and
+// Bitbucket is base64 encoded and gets decoded, while text that isn't base64 (e.g. from pkg.go.dev)
+// is returned as is. Empty string is returned when license text wasn't fetched.
+func (r *Repository) LicenseText() (string, error) {
+	if r.Text == "" || !isBase64(r.Text) {
+		return r.Text, nil
+	}
+	dec, err := base64.StdEncoding.DecodeString(r.Text)
+	if err != nil {
+		return "", fmt.Errorf("could not decode license text of %s: %w", r.Name, err)
+	}
+	return string(dec), nil
+}
+
+// setLicenseHash sets LicenseHash of r from its license text, leaving it empty when r has no text
+func setLicenseHash(r *Repository) {
+	r.LicenseHash = ""
+	text, err := r.LicenseText()
+	if err != nil || text == "" {
+		return
+	}
+	sum := sha256.Sum256([]byte(text))
+	r.LicenseHash = hex.EncodeToString(sum[:])
+}
+
+// isBase64 reports whether s consists only of base64 alphabet and line breaks, which APIs wrap it with
+func isBase64(s string) bool {
+	return strings.IndexFunc(s, func(c rune) bool {
+		return !(c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' ||
+			c == '+' || c == '/' || c == '=' || c == '\n' || c == '\r')
+	}) < 0
+}
+
+type gitOption func(*gitClient)
+
+// withLogger sets logger of client configuration problems, nil keeps slog.Default()
+func withLogger(l *slog.Logger) gitOption {
+	return func(gc *gitClient) {
+		if l != nil {
+			gc.logger = l
+		}
+	}
+}
+
+// withHTTPClient sets base HTTP client for API requests, nil keeps the default one
+func withHTTPClient(hc *http.Client) gitOption {
+	return func(gc *gitClient) {
+		if hc != nil {
+			gc.httpClient = hc
+		}
+	}
+}
+
+func withTimeout(d time.Duration) gitOption {
+	return func(gc *gitClient) {
+		gc.timeout = d
+	}
+}
+
+func withRetry(p retryPolicy) gitOption {
+	return func(gc *gitClient) {
+		gc.retry = p
+	}
+}
+
+// withGitHubBaseURL makes GitHub API requests go to GitHub Enterprise Server at u
+func withGitHubBaseURL(u string) gitOption {
+	return func(gc *gitClient) {
+		gc.githubBaseURL = u
+	}
+}
+
+// withGitLabHosts adds GitLab clients for self-hosted instances at hosts
+func withGitLabHosts(hosts []string) gitOption {
+	return func(gc *gitClient) {
+		gc.gitlabHosts = hosts
+	}
+}
+
+// withCollectorFactory makes pkg.go.dev scraping use collectors created by fn, e.g. ones serving fixture HTML in tests
+func withCollectorFactory(fn func() *colly.Collector) gitOption {
+	return func(gc *gitClient) {
+		gc.collectorFactory = fn
+	}
+}
+
+// withActivity makes GitHub license requests fetch repository activity as well
+func withActivity(enabled bool) gitOption {
+	return func(gc *gitClient) {
+		gc.activity = enabled
+	}
+}
+
+// withTaggedLicenses makes GitHub license requests read license file at the tag of dependency version
+func withTaggedLicenses(enabled bool) gitOption {
+	return func(gc *gitClient) {
+		gc.taggedLicenses = enabled
+	}
+}
+
+// withLicenseFiles makes GitHub license requests fetch all license and notice files as well
+func withLicenseFiles(enabled bool) gitOption {
+	return func(gc *gitClient) {
+		gc.licenseFiles = enabled
+	}
+}
+
+func newGitClient(c context.Context, keys map[string]string, star bool, opts ...gitOption) *gitClient {
+	gc := &gitClient{star: star, logger: slog.Default()}
+	for _, opt := range opts {
+		opt(gc)
+	}
+
+	hc := gc.httpClient
+	if hc == nil {
+		hc = &http.Client{Timeout: gc.timeout}
+	}
+
+	tc := hc
 	var ghLogged bool
 	if v := keys["github.com"]; v != "" {
 		ts := oauth2.StaticTokenSource(
 			&oauth2.Token{AccessToken: v},
 		)
-		tc = oauth2.NewClient(c, ts)
+		tc = oauth2.NewClient(context.WithValue(c, oauth2.HTTPClient, hc), ts)
+		tc.Timeout = hc.Timeout
 		ghLogged = true
 	}
-	return &gitClient{
-		gh: githubClient{
-			Client: github.NewClient(tc),
-			logged: ghLogged,
-		},
-		star: star,
+
+	gc.gl = map[string]*gitlab.Client{}
+	if glc, err := gitlab.NewClient(keys["gitlab.com"], gitlab.WithHTTPClient(hc)); err == nil {
+		gc.gl["gitlab.com"] = glc
+	}
+	for _, host := range gc.gitlabHosts {
+		glc, err := gitlab.NewClient(keys[host], gitlab.WithHTTPClient(hc), gitlab.WithBaseURL("https://"+host))
+		if err != nil {
+			gc.logger.Warn("Could not create GitLab client", "host", host, "error", err)
+			continue
+		}
+		gc.gl[host] = glc
+	}
+
+	// bitbucket.org key holds "username:app-password" pair used for basic auth
+	bbUser, bbPassword, _ := strings.Cut(keys["bitbucket.org"], ":")
+
+	gh := github.NewClient(tc)
+	if gc.githubBaseURL != "" {
+		ghe, err := github.NewEnterpriseClient(gc.githubBaseURL, gc.githubBaseURL, tc)
+		if err != nil {
+			gc.logger.Warn("Invalid GitHub API URL, falling back to api.github.com", "url", gc.githubBaseURL, "error", err)
+		} else {
+			gh = ghe
+		}
+	}
+
+	gc.gh = githubClient{
+		Client: gh,
+		logged: ghLogged,
+	}
+	// GraphQL API can't be used without authentication
+	if ghLogged {
+		gc.ghv4 = githubv4.NewClient(tc)
+		if gc.githubBaseURL != "" {
+			gc.ghv4 = githubv4.NewEnterpriseClient(graphQLURL(gc.githubBaseURL), tc)
+		}
 	}
+	gc.gitea = giteaClient{
+		Client: hc,
+		scheme: "https",
+		keys:   keys,
+	}
+	gc.bb = bitbucketClient{
+		Client:   hc,
+		baseURL:  "https://api.bitbucket.org",
+		username: bbUser,
+		password: bbPassword,
+	}
+	gc.httpClient = hc
+	return gc
 }
 
 type gitClient struct {
-	gh   githubClient
-	star bool
+	gh         githubClient
+	ghv4       *githubv4.Client
+	gl         map[string]*gitlab.Client
+	bb         bitbucketClient
+	gitea      giteaClient
+	httpClient *http.Client
+	timeout    time.Duration
+	retry      retryPolicy
+	star       bool
+	logger     *slog.Logger
+	// githubBaseURL is API URL of GitHub Enterprise Server, api.github.com is used when empty
+	githubBaseURL string
+	// gitlabHosts are hostnames of self-hosted GitLab instances
+	gitlabHosts []string
+	// collectorFactory creates collectors scraping pkg.go.dev, newCollector is used when nil
+	collectorFactory func() *colly.Collector
+	// activity fetches archival and last push time of GitHub repositories
+	activity bool
+	// licenseFiles fetches all license and notice files of GitHub repositories
+	licenseFiles bool
+	// taggedLicenses reads licenses of GitHub repositories at the tag of their version
+	taggedLicenses bool
+}
+
+// newCollector returns collector scraping pkg.go.dev with requests limited by timeout
+func newCollector(timeout time.Duration, opts ...func(*colly.Collector)) *colly.Collector {
+	c := colly.NewCollector(append([]func(*colly.Collector){
+		colly.MaxDepth(2),
+		colly.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/58.0.3029.110 Safari/537.3"),
+	}, opts...)...)
+	c.SetRequestTimeout(timeout)
+	return c
+}
+
+// collector returns collector from collectorFactory, or a new one using transport of HTTP client
+func (gc *gitClient) collector() *colly.Collector {
+	if gc.collectorFactory != nil {
+		return gc.collectorFactory()
+	}
+	c := newCollector(gc.requestTimeout())
+	if gc.httpClient != nil && gc.httpClient.Transport != nil {
+		c.WithTransport(gc.httpClient.Transport)
+	}
+	return c
+}
+
+type bitbucketClient struct {
+	*http.Client
+	baseURL  string
+	username string
+	password string
+}
+
+// rawLicense fetches LICENSE file from the main branch using Bitbucket Cloud REST API v2
+func (bc bitbucketClient) rawLicense(ctx context.Context, workspace, slug string) ([]byte, *http.Response, error) {
+	u := fmt.Sprintf("%s/2.0/repositories/%s/%s/src/HEAD/LICENSE", bc.baseURL, workspace, slug)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if bc.username != "" {
+		req.SetBasicAuth(bc.username, bc.password)
+	}
+
+	resp, err := bc.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp, fmt.Errorf("GET %s: %s", u, resp.Status)
+	}
+	bts, err := io.ReadAll(resp.Body)
+	return bts, resp, err
+}
+
+type giteaClient struct {
+	*http.Client
+	scheme string
+	// keys are API tokens per host, gitea key is used for hosts without their own
+	keys map[string]string
+}
+
+// giteaRepository is repository returned by Gitea API. Older Gitea versions report license
+// in license field, newer in licenses list.
+type giteaRepository struct {
+	DefaultBranch string   `json:"default_branch"`
+	License       string   `json:"license"`
+	Licenses      []string `json:"licenses"`
+}
+
+// spdx returns SPDX identifier of the first license reported by Gitea
+func (gr giteaRepository) spdx() string {
+	if gr.License != "" {
+		return gr.License
+	}
+	if len(gr.Licenses) > 0 {
+		return gr.Licenses[0]
+	}
+	return ""
+}
+
+// get fetches path of host using Gitea REST API v1
+func (tc giteaClient) get(ctx context.Context, host, path string) ([]byte, *http.Response, error) {
+	u := fmt.Sprintf("%s://%s/api/v1/%s", tc.scheme, host, path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := tc.keys[host]
+	if key == "" {
+		key = tc.keys["gitea"]
+	}
+	if key != "" {
+		req.Header.Set("Authorization", "token "+key)
+	}
+
+	resp, err := tc.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp, fmt.Errorf("GET %s: %s", u, resp.Status)
+	}
+	bts, err := io.ReadAll(resp.Body)
+	return bts, resp, err
 }
 
 type githubClient struct {
@@ -99,55 +540,355 @@ type githubClient struct {
 
 // GetLicense for a repository
 func (gc *gitClient) GetLicense(ctx context.Context, r *Repository) error {
-	switch r.Host {
-	case "github.com":
-		rl, _, err := gc.gh.Repositories.License(ctx, r.Author, r.Project)
+	defer classifyLicense(r)
+
+	gl, isGitLab := gc.gl[r.Host]
+	switch {
+	case r.Host == "github.com":
+		var rl *github.RepositoryLicense
+		err := gc.do(ctx, func(ctx context.Context) (*http.Response, error) {
+			var resp *github.Response
+			var err error
+			rl, resp, err = gc.gh.Repositories.License(ctx, r.Author, r.Project)
+			return githubResponse(resp), err
+		})
 		if err != nil {
 			return err
 		}
 
-		name, clr := licenseCol[*rl.License.Key].name, licenseCol[*rl.License.Key].color
-		if name == "" {
-			name = *rl.License.Key
-			clr = color.FgYellow
-		}
-		r.Shortname = color.New(clr).Sprintf(name)
-		r.License = name
+		key := rl.License.GetKey()
 		r.Text = rl.GetContent()
+		r.LicenseURL = rl.GetDownloadURL()
+		r.CommitSHA = rl.GetSHA()
+		if gc.taggedLicenses && gc.setGitHubTaggedLicense(ctx, r, rl.GetPath()) {
+			// license could've changed since the tag, so key GitHub detected at HEAD is kept only
+			// when text at the tag isn't classified as a different license
+			if text, err := r.LicenseText(); err == nil {
+				if tagged := classifyKey(text); tagged != "other" {
+					key = tagged
+				}
+			}
+		}
+		// GitHub reports licenses it can't identify as other, so they're classified from their text
+		if text, err := r.LicenseText(); (key == "" || key == "other") && err == nil && text != "" {
+			key = classifyKey(text)
+		}
+		setLicense(r, key)
+		starred := gc.starGitHub(ctx, r)
+		if gc.activity || starred {
+			if err := gc.setGitHubDetails(ctx, r); err != nil {
+				return err
+			}
+		}
+		if gc.licenseFiles {
+			if err := gc.setGitHubLicenseFiles(ctx, r); err != nil {
+				return err
+			}
+		}
+	case isGitLab:
+		pid := r.Author + "/" + r.Project
+		var p *gitlab.Project
+		err := gc.do(ctx, func(ctx context.Context) (*http.Response, error) {
+			var resp *gitlab.Response
+			var err error
+			p, resp, err = gl.Projects.GetProject(pid, &gitlab.GetProjectOptions{License: gitlab.Bool(true)}, gitlab.WithContext(ctx))
+			return gitlabResponse(resp), err
+		})
+		if err != nil {
+			return err
+		}
+		if p.License != nil {
+			setLicense(r, p.License.Key)
+		}
+		r.LicenseURL = p.LicenseURL
 
-		if gc.star && gc.gh.logged {
-			gc.gh.Activity.Star(ctx, r.Author, r.Project)
+		var raw []byte
+		err = gc.do(ctx, func(ctx context.Context) (*http.Response, error) {
+			var resp *gitlab.Response
+			var err error
+			raw, resp, err = gl.RepositoryFiles.GetRawFile(pid, "LICENSE", &gitlab.GetRawFileOptions{Ref: gitlab.String(p.DefaultBranch)}, gitlab.WithContext(ctx))
+			return gitlabResponse(resp), err
+		})
+		if err != nil {
+			return err
+		}
+		// Text is kept base64 encoded, the same way GitHub API returns it
+		r.Text = base64.StdEncoding.EncodeToString(raw)
+	case r.Host == "gitea":
+		var raw []byte
+		repoPath := "repos/" + r.Author + "/" + r.Project
+		err := gc.do(ctx, func(ctx context.Context) (*http.Response, error) {
+			var resp *http.Response
+			var err error
+			raw, resp, err = gc.gitea.get(ctx, r.HostURL, repoPath)
+			return resp, err
+		})
+		if err != nil {
+			return err
+		}
+		var gr giteaRepository
+		if err := json.Unmarshal(raw, &gr); err != nil {
+			return err
+		}
+
+		err = gc.do(ctx, func(ctx context.Context) (*http.Response, error) {
+			var resp *http.Response
+			var err error
+			raw, resp, err = gc.gitea.get(ctx, r.HostURL, repoPath+"/raw/LICENSE")
+			return resp, err
+		})
+		if err != nil {
+			return err
+		}
+
+		// license is classified from its text when Gitea doesn't report it
+		key := strings.ToLower(gr.spdx())
+		if key == "" {
+			key = classifyKey(string(raw))
+		}
+		setLicense(r, key)
+		r.Text = base64.StdEncoding.EncodeToString(raw)
+		if gr.DefaultBranch != "" {
+			r.LicenseURL = fmt.Sprintf("https://%s/%s/%s/src/branch/%s/LICENSE", r.HostURL, r.Author, r.Project, gr.DefaultBranch)
+		}
+	case r.Host == "bitbucket.org":
+		var raw []byte
+		err := gc.do(ctx, func(ctx context.Context) (*http.Response, error) {
+			var resp *http.Response
+			var err error
+			raw, resp, err = gc.bb.rawLicense(ctx, r.Author, r.Project)
+			return resp, err
+		})
+		if err != nil {
+			return err
 		}
-	case "pkg.go.dev":
-		c := colly.NewCollector(
-			colly.MaxDepth(2),
-			colly.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/58.0.3029.110 Safari/537.3"),
-		)
-		c.SetRequestTimeout(10 * time.Second)
 
+		// Bitbucket doesn't detect licenses, so it is classified from the license text
+		setLicense(r, classifyKey(string(raw)))
+		r.Text = base64.StdEncoding.EncodeToString(raw)
+	default:
+		// hosts without supported API are scraped from pkg.go.dev
+		c := gc.collector()
+		// colly doesn't support contexts, so requests are aborted once ctx is done
+		c.OnRequest(func(req *colly.Request) {
+			if ctx.Err() != nil {
+				req.Abort()
+			}
+		})
+
+		// versionErr is set when version header doesn't look as expected, e.g. when page layout changed
+		var versionErr error
 		c.OnHTML("span[data-test-id=\"UnitHeader-version\"]", func(e *colly.HTMLElement) {
-			version := e.ChildText("a")
-			version = version[9:]
+			text := e.ChildText("a")
+			version, ok := strings.CutPrefix(text, "Version: ")
+			if !ok {
+				versionErr = fmt.Errorf("unexpected version %q on pkg.go.dev page of %s", text, r.Name)
+				return
+			}
 			version = strings.Split(version, "G")[0]
 			version = strings.TrimSpace(version)
-			if !strings.EqualFold(r.Version, version) {
-				r.Version = fmt.Sprintf("%s (!new:%s)", r.Version, version)
-			}
+			r.LatestVersion = version
+			r.IsOutdated = semver.Compare(r.Version, version) < 0
 		})
 		c.OnHTML("span[data-test-id=\"UnitHeader-licenses\"]", func(e *colly.HTMLElement) {
 			license := e.ChildText("a")
-			r.Shortname = color.New(getLicenseColor(license)).Sprintf(license)
+			r.License = license
+			r.LicenseURL = "https://pkg.go.dev/" + r.Name + "?tab=licenses"
+			if href := e.ChildAttr("a", "href"); href != "" {
+				r.LicenseURL = e.Request.AbsoluteURL(href)
+			}
+			r.Shortname = license
+			// pkg.go.dev displays SPDX identifiers, multiple licenses are comma separated
+			if spdxLicenseID.MatchString(license) {
+				r.LicenseSPDX = license
+			}
 		})
 		c.OnHTML(".UnitMeta-repo", func(e *colly.HTMLElement) {
 			repo := e.ChildText("a")
 			r.Project = repo
 		})
 
-		err := c.Visit(r.URL)
-		if err != nil {
-			fmt.Println(r.URL, "error:", err)
+		// status of failed response, 0 when request failed without response
+		var status int
+		c.OnError(func(resp *colly.Response, _ error) {
+			status = resp.StatusCode
+		})
+
+		url := "https://pkg.go.dev/" + r.Name
+		if err := c.Visit(url); err != nil {
+			err = fmt.Errorf("visit %s: %w", url, err)
+			if ctx.Err() == nil && (status == 0 || status == http.StatusTooManyRequests || status >= http.StatusInternalServerError) {
+				return transientError{err}
+			}
+			return err
+		}
+		if versionErr != nil {
+			return versionErr
 		}
 	}
 
 	return nil
 }
+
+// setGitHubDetails sets number of stars of GitHub repository r, whether it is archived and
+// when it was last pushed to
+func (gc *gitClient) setGitHubDetails(ctx context.Context, r *Repository) error {
+	var repo *github.Repository
+	err := gc.do(ctx, func(ctx context.Context) (*http.Response, error) {
+		var resp *github.Response
+		var err error
+		repo, resp, err = gc.gh.Repositories.Get(ctx, r.Author, r.Project)
+		return githubResponse(resp), err
+	})
+	if err != nil {
+		return err
+	}
+
+	r.Stars = repo.GetStargazersCount()
+	r.IsArchived = repo.GetArchived()
+	if repo.PushedAt != nil {
+		pushed := repo.PushedAt.Time
+		r.PushedAt = &pushed
+	}
+	// REST API doesn't report when repository was archived, but archiving is its last update
+	if r.IsArchived && repo.UpdatedAt != nil {
+		archived := repo.UpdatedAt.Time
+		r.ArchivedAt = &archived
+	}
+	return nil
+}
+
+// setGitHubLicenseFiles sets license and notice files in root of GitHub repository r,
+// fetching each of them with a separate request
+func (gc *gitClient) setGitHubLicenseFiles(ctx context.Context, r *Repository) error {
+	var dir []*github.RepositoryContent
+	err := gc.do(ctx, func(ctx context.Context) (*http.Response, error) {
+		var resp *github.Response
+		var err error
+		_, dir, resp, err = gc.gh.Repositories.GetContents(ctx, r.Author, r.Project, "", nil)
+		return githubResponse(resp), err
+	})
+	if err != nil {
+		return err
+	}
+
+	r.LicenseFiles = nil
+	for _, entry := range dir {
+		if entry.GetType() != "file" || !isLicenseFile(entry.GetName()) {
+			continue
+		}
+		var file *github.RepositoryContent
+		err := gc.do(ctx, func(ctx context.Context) (*http.Response, error) {
+			var resp *github.Response
+			var err error
+			file, _, resp, err = gc.gh.Repositories.GetContents(ctx, r.Author, r.Project, entry.GetPath(), nil)
+			return githubResponse(resp), err
+		})
+		if err != nil {
+			return err
+		}
+		text, err := file.GetContent()
+		if err != nil {
+			return fmt.Errorf("could not decode %s of %s: %w", entry.GetName(), r.Name, err)
+		}
+		r.LicenseFiles = append(r.LicenseFiles, LicenseFile{Name: entry.GetName(), Text: text, SPDX: detectLicenseKey(text)})
+	}
+	return nil
+}
+
+// setGitHubTaggedLicense replaces license text of r fetched at HEAD with license file at path read at
+// the tag of r.Version, reporting whether it was replaced. GitHub License API only returns license of
+// the default branch, which doesn't have to match the version in use. HEAD license is kept when
+// the version isn't tagged, or the file doesn't exist at the tag.
+func (gc *gitClient) setGitHubTaggedLicense(ctx context.Context, r *Repository, path string) bool {
+	tag := githubTag(r)
+	if tag == "" || path == "" {
+		return false
+	}
+	var file *github.RepositoryContent
+	err := gc.do(ctx, func(ctx context.Context) (*http.Response, error) {
+		var resp *github.Response
+		var err error
+		file, _, resp, err = gc.gh.Repositories.GetContents(ctx, r.Author, r.Project, path, &github.RepositoryContentGetOptions{Ref: tag})
+		return githubResponse(resp), err
+	})
+	if err != nil || file == nil {
+		return false
+	}
+	text, err := file.GetContent()
+	if err != nil {
+		return false
+	}
+	if head, err := r.LicenseText(); err == nil && head == text {
+		return false
+	}
+	r.Text = base64.StdEncoding.EncodeToString([]byte(text))
+	r.LicenseURL = file.GetDownloadURL()
+	r.CommitSHA = file.GetSHA()
+	return true
+}
+
+// githubTag returns git tag of r.Version, or empty string when it isn't a full semver release or
+// pre-release version. Tags of modules in subdirectories are prefixed with the subdirectory.
+func githubTag(r *Repository) string {
+	v := strings.TrimSuffix(r.Version, "+incompatible")
+	if semver.Canonical(v) != v || module.IsPseudoVersion(v) {
+		return ""
+	}
+	base := "github.com/" + r.Author + "/" + r.Project
+	if prefix, _, ok := module.SplitPathVersion(r.Name); ok && strings.HasPrefix(prefix, base+"/") {
+		return strings.TrimPrefix(prefix, base+"/") + "/" + v
+	}
+	return v
+}
+
+// starGitHub stars GitHub repository r if starring was requested and API key was provided,
+// reporting whether it was starred
+func (gc *gitClient) starGitHub(ctx context.Context, r *Repository) bool {
+	if !gc.star || !gc.gh.logged {
+		return false
+	}
+	err := gc.do(ctx, func(ctx context.Context) (*http.Response, error) {
+		resp, err := gc.gh.Activity.Star(ctx, r.Author, r.Project)
+		return githubResponse(resp), err
+	})
+	return err == nil
+}
+
+// requestTimeout returns timeout for a single API request
+func (gc *gitClient) requestTimeout() time.Duration {
+	if gc.timeout > 0 {
+		return gc.timeout
+	}
+	return defaultTimeout
+}
+
+// requestContext limits ctx to request timeout, deadline of ctx is still respected if it's sooner
+func (gc *gitClient) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, gc.requestTimeout())
+}
+
+func githubResponse(resp *github.Response) *http.Response {
+	if resp == nil {
+		return nil
+	}
+	return resp.Response
+}
+
+func gitlabResponse(resp *gitlab.Response) *http.Response {
+	if resp == nil {
+		return nil
+	}
+	return resp.Response
+}
+
+// setLicense sets license name and shortname from license key used by GitHub and GitLab APIs,
+// see licenseName
+func setLicense(r *Repository, key string) {
+	name := licenseName(key)
+	r.Shortname = name
+	r.License = name
+	if key != "other" {
+		r.LicenseSPDX = name
+	}
+}
@@ -2,9 +2,19 @@ package glice
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/gocolly/colly"
+	"github.com/xanzy/go-gitlab"
 )
 
 func TestGitHubAPINoKey(t *testing.T) {
@@ -44,7 +54,7 @@ func TestNonexistentLicense(t *testing.T) {
 		t.Error(err)
 	}
 
-	if l.Shortname != color.New(color.FgYellow).Sprintf("wtfpl") {
+	if l.Shortname != "WTFPL" {
 		t.Errorf("API did not return correct license or color.")
 	}
 
@@ -94,3 +104,507 @@ func TestGitHubAPIWithKeyAndThanks(t *testing.T) {
 	}
 
 }
+
+func TestGitHubEnterpriseAPI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/ribice/kiss/license" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer apikey" {
+			t.Error("expected API key to be sent")
+		}
+		fmt.Fprint(w, `{"content": "bGljZW5zZS10ZXh0", "license": {"key": "mit", "name": "MIT License"}}`)
+	}))
+	defer srv.Close()
+
+	c := context.Background()
+	gc := newGitClient(c, map[string]string{"github.com": "apikey"}, false, withGitHubBaseURL(srv.URL+"/api/v3"))
+
+	l := &Repository{Host: "github.com", Author: "ribice", Project: "kiss"}
+	if err := gc.GetLicense(c, l); err != nil {
+		t.Fatal(err)
+	}
+	if l.License != "MIT" || l.Text != "bGljZW5zZS10ZXh0" {
+		t.Errorf("API did not return correct license, got %+v", l)
+	}
+}
+
+func TestGitLabAPI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/ribice%2Fkiss":
+			if r.URL.Query().Get("license") != "true" {
+				t.Error("expected license to be requested")
+			}
+			fmt.Fprint(w, `{"id": 1, "default_branch": "main", "license": {"key": "mit", "name": "MIT License"}}`)
+		case "/api/v4/projects/ribice%2Fkiss/repository/files/LICENSE/raw":
+			if r.URL.Query().Get("ref") != "main" {
+				t.Error("expected license to be fetched from default branch")
+			}
+			fmt.Fprint(w, "license-text")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := context.Background()
+	gc := newGitClient(c, map[string]string{}, false)
+	glc, err := gitlab.NewClient("", gitlab.WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gc.gl["gitlab.com"] = glc
+
+	l := &Repository{Host: "gitlab.com", Author: "ribice", Project: "kiss"}
+	if err := gc.GetLicense(c, l); err != nil {
+		t.Fatal(err)
+	}
+
+	if l.License != "MIT" || l.Shortname != "MIT" {
+		t.Errorf("API did not return correct license or shortname.")
+	}
+	if l.Text != "bGljZW5zZS10ZXh0" {
+		t.Errorf("expected base64 encoded license text, got %s", l.Text)
+	}
+	if l.Category != CategoryPermissive {
+		t.Errorf("expected permissive category, got %s", l.Category)
+	}
+}
+
+func TestSetLicense(t *testing.T) {
+	tests := map[string]struct {
+		key      string
+		wantName string
+		wantSPDX string
+	}{
+		"known":        {key: "apache-2.0", wantName: "Apache-2.0", wantSPDX: "Apache-2.0"},
+		"without name": {key: "bsd-3-clause", wantName: "BSD-3-Clause", wantSPDX: "BSD-3-Clause"},
+		"unknown":      {key: "custom-1.0", wantName: "custom-1.0", wantSPDX: "custom-1.0"},
+		"other":        {key: "other", wantName: "Other"},
+		"gpl-2.0":      {key: "gpl-2.0", wantName: "GPL-2.0-only", wantSPDX: "GPL-2.0-only"},
+		"gpl-3.0":      {key: "gpl-3.0", wantName: "GPL-3.0-only", wantSPDX: "GPL-3.0-only"},
+		"lgpl-2.1":     {key: "lgpl-2.1", wantName: "LGPL-2.1-only", wantSPDX: "LGPL-2.1-only"},
+		"lgpl-3.0":     {key: "lgpl-3.0", wantName: "LGPL-3.0-only", wantSPDX: "LGPL-3.0-only"},
+		"agpl-3.0":     {key: "agpl-3.0", wantName: "AGPL-3.0-only", wantSPDX: "AGPL-3.0-only"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &Repository{}
+			setLicense(r, tt.key)
+			if r.License != tt.wantName || r.LicenseSPDX != tt.wantSPDX {
+				t.Errorf("setLicense() = %s, %s, want %s, %s", r.License, r.LicenseSPDX, tt.wantName, tt.wantSPDX)
+			}
+		})
+	}
+
+	bts, err := json.Marshal(&Repository{License: "Apache-2.0", LicenseSPDX: "apache-2.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(bts), `"license_spdx":"apache-2.0"`) {
+		t.Errorf("expected license_spdx in JSON, got %s", bts)
+	}
+}
+
+func TestLicenseCategory(t *testing.T) {
+	tests := map[string]string{
+		"MIT":          CategoryPermissive,
+		"apache-2.0":   CategoryPermissive,
+		"LGPL-2.1":     CategoryWeakCopyleft,
+		"MPL-2.0":      CategoryWeakCopyleft,
+		"GPL-3.0":      CategoryStrongCopyleft,
+		"GPL-2.0-only": CategoryStrongCopyleft,
+		"LGPL-2.1+":    CategoryWeakCopyleft,
+		"AGPL-3.0":     CategoryNetworkCopyleft,
+		"Unlicense":    CategoryPublicDomain,
+		"CC0-1.0":      CategoryPublicDomain,
+		"other":        CategoryUnknown,
+		"":             CategoryUnknown,
+		"Proprietary":  CategoryUnknown,
+	}
+	for id, want := range tests {
+		if got := LicenseCategory(id); got != want {
+			t.Errorf("LicenseCategory(%q) = %s, want %s", id, got, want)
+		}
+	}
+	for key := range licenseColMap {
+		if _, ok := licenseCategories[key]; !ok && key != "other" {
+			t.Errorf("license %s has no category", key)
+		}
+	}
+}
+
+func TestSelfHostedGitLabAPI(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Private-Token") != "token" {
+			t.Error("expected host API token to be sent")
+		}
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/ribice%2Fkiss":
+			fmt.Fprint(w, `{"id": 1, "default_branch": "main", "license": {"key": "apache-2.0"}}`)
+		case "/api/v4/projects/ribice%2Fkiss/repository/files/LICENSE/raw":
+			fmt.Fprint(w, "license-text")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	host := srv.Listener.Addr().String()
+	c := context.Background()
+	gc := newGitClient(c, map[string]string{host: "token"}, false, withHTTPClient(srv.Client()), withGitLabHosts([]string{host}))
+	if _, ok := gc.gl[host]; !ok {
+		t.Fatalf("expected GitLab client for %s", host)
+	}
+
+	l := &Repository{Host: host, Author: "ribice", Project: "kiss"}
+	if err := gc.GetLicense(c, l); err != nil {
+		t.Fatal(err)
+	}
+	if l.License != "Apache-2.0" || l.Text != "bGljZW5zZS10ZXh0" {
+		t.Errorf("API did not return correct license, got %+v", l)
+	}
+}
+
+func TestBitbucketAPI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2.0/repositories/ribice/kiss/src/HEAD/LICENSE" {
+			http.NotFound(w, r)
+			return
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "ribice" || pass != "secret" {
+			t.Error("expected basic auth credentials")
+		}
+		fmt.Fprint(w, "Permission is hereby granted, free of charge, to any person obtaining a copy of this software")
+	}))
+	defer srv.Close()
+
+	c := context.Background()
+	gc := newGitClient(c, map[string]string{"bitbucket.org": "ribice:secret"}, false)
+	gc.bb.baseURL = srv.URL
+
+	l := &Repository{Host: "bitbucket.org", Author: "ribice", Project: "kiss"}
+	if err := gc.GetLicense(c, l); err != nil {
+		t.Fatal(err)
+	}
+	if l.License != "MIT" || l.Shortname != "MIT" {
+		t.Errorf("API did not return correct license or shortname.")
+	}
+
+	l = &Repository{Host: "bitbucket.org", Author: "ribice", Project: "missing"}
+	if err := gc.GetLicense(c, l); err == nil {
+		t.Error("expected error for missing license file")
+	}
+}
+
+func TestGiteaAPI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token secret" {
+			t.Errorf("expected API token, got %q", r.Header.Get("Authorization"))
+		}
+		switch r.URL.Path {
+		case "/api/v1/repos/ribice/kiss":
+			fmt.Fprint(w, `{"default_branch": "main", "licenses": ["MIT"]}`)
+		case "/api/v1/repos/ribice/undetected":
+			fmt.Fprint(w, `{"default_branch": "main"}`)
+		case "/api/v1/repos/ribice/kiss/raw/LICENSE", "/api/v1/repos/ribice/undetected/raw/LICENSE":
+			fmt.Fprint(w, "Permission is hereby granted, free of charge, to any person obtaining a copy of this software")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	c := context.Background()
+	gc := newGitClient(c, map[string]string{"gitea": "secret"}, false)
+	gc.gitea.scheme = "http"
+
+	tests := map[string]struct {
+		project     string
+		wantLicense string
+		wantErr     bool
+	}{
+		"reported license": {project: "kiss", wantLicense: "MIT"},
+		"detected license": {project: "undetected", wantLicense: "MIT"},
+		"missing":          {project: "missing", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			l := &Repository{Host: "gitea", HostURL: host, Author: "ribice", Project: tt.project}
+			err := gc.GetLicense(c, l)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetLicense() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if l.License != tt.wantLicense || l.LicenseSPDX != "MIT" || l.Text == "" {
+				t.Errorf("API did not return correct license, got %+v", l)
+			}
+			if want := "https://" + host + "/ribice/" + tt.project + "/src/branch/main/LICENSE"; l.LicenseURL != want {
+				t.Errorf("LicenseURL = %s, want %s", l.LicenseURL, want)
+			}
+		})
+	}
+}
+
+func TestGetLicenseTimeout(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	c := context.Background()
+	gc := newGitClient(c, map[string]string{}, false, withTimeout(50*time.Millisecond))
+	gc.bb.baseURL = srv.URL
+
+	start := time.Now()
+	if err := gc.GetLicense(c, &Repository{Host: "bitbucket.org", Author: "ribice", Project: "kiss"}); err == nil {
+		t.Error("expected timeout error")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("request was not cancelled after timeout")
+	}
+
+	cancelled, cancel := context.WithCancel(c)
+	cancel()
+	gc = newGitClient(c, map[string]string{}, false)
+	gc.bb.baseURL = srv.URL
+	if err := gc.GetLicense(cancelled, &Repository{Host: "bitbucket.org", Author: "ribice", Project: "kiss"}); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
+
+func TestGitHubActivity(t *testing.T) {
+	hc := NewMockHTTPClient(map[string]string{
+		"https://api.github.com/repos/ribice/kiss/license": `{"content": "bGljZW5zZS10ZXh0", "license": {"key": "mit", "name": "MIT License"}}`,
+		"https://api.github.com/repos/ribice/kiss":         `{"archived": true, "stargazers_count": 42, "pushed_at": "2019-01-02T03:04:05Z", "updated_at": "2021-01-02T03:04:05Z"}`,
+	})
+	c := context.Background()
+
+	tests := map[string]struct {
+		activity     bool
+		wantArchived bool
+	}{
+		"activity":    {activity: true, wantArchived: true},
+		"no activity": {},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gc := newGitClient(c, map[string]string{}, false, withHTTPClient(hc), withActivity(tt.activity))
+			l := &Repository{Host: "github.com", Author: "ribice", Project: "kiss"}
+			if err := gc.GetLicense(c, l); err != nil {
+				t.Fatal(err)
+			}
+			if l.License != "MIT" || l.IsArchived != tt.wantArchived {
+				t.Fatalf("unexpected repository %+v", l)
+			}
+			if !tt.wantArchived {
+				return
+			}
+			if l.Stars != 42 || l.PushedAt == nil || l.PushedAt.Year() != 2019 || l.ArchivedAt == nil || l.ArchivedAt.Year() != 2021 {
+				t.Errorf("expected stars, push and archival time, got %d, %v and %v", l.Stars, l.PushedAt, l.ArchivedAt)
+			}
+		})
+	}
+}
+
+func TestGitHubClassifyOther(t *testing.T) {
+	hc := NewMockHTTPClient(map[string]string{
+		"https://api.github.com/repos/davecgh/go-spew/license": fmt.Sprintf(`{"content": %q, "license": {"key": "other", "name": "Other"}}`, base64.StdEncoding.EncodeToString([]byte(iscText))),
+	})
+	c := context.Background()
+	gc := newGitClient(c, map[string]string{}, false, withHTTPClient(hc))
+
+	l := &Repository{Host: "github.com", Author: "davecgh", Project: "go-spew"}
+	if err := gc.GetLicense(c, l); err != nil {
+		t.Fatal(err)
+	}
+	if l.License != "ISC" || l.LicenseSPDX != "ISC" {
+		t.Errorf("expected license GitHub reports as other to be classified from text, got %s (%s)", l.License, l.LicenseSPDX)
+	}
+}
+
+func TestRepository_LicenseText(t *testing.T) {
+	tests := map[string]struct {
+		text    string
+		want    string
+		wantErr bool
+	}{
+		"empty":          {},
+		"base64":         {text: "bGljZW5zZS10ZXh0", want: "license-text"},
+		"wrapped base64": {text: "bGljZW5z\nZS10ZXh0\n", want: "license-text"},
+		"plain text":     {text: "MIT License\n\nCopyright (c) 2024", want: "MIT License\n\nCopyright (c) 2024"},
+		"corrupted":      {text: "bGljZW5zZS10ZXh", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := (&Repository{Name: "github.com/ribice/glice", Text: tt.text}).LicenseText()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LicenseText() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("LicenseText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitHubLicenseFiles(t *testing.T) {
+	hc := NewMockHTTPClient(map[string]string{
+		"https://api.github.com/repos/ribice/kiss/license": `{"content": "bGljZW5zZS10ZXh0", "license": {"key": "mit", "name": "MIT License"}}`,
+		"https://api.github.com/repos/ribice/kiss/contents/": `[
+			{"type": "file", "name": "LICENSE", "path": "LICENSE"},
+			{"type": "file", "name": "NOTICE.md", "path": "NOTICE.md"},
+			{"type": "file", "name": "README.md", "path": "README.md"},
+			{"type": "dir", "name": "licenses", "path": "licenses"}
+		]`,
+		"https://api.github.com/repos/ribice/kiss/contents/LICENSE":   `{"type": "file", "name": "LICENSE", "encoding": "base64", "content": "UGVybWlzc2lvbiBpcyBoZXJlYnkgZ3JhbnRlZCwgZnJlZSBvZiBjaGFyZ2UsIHRvIGFueSBwZXJzb24gb2J0YWluaW5nIGEgY29weSBvZiB0aGlzIHNvZnR3YXJl"}`,
+		"https://api.github.com/repos/ribice/kiss/contents/NOTICE.md": `{"type": "file", "name": "NOTICE.md", "encoding": "base64", "content": "bm90aWNl"}`,
+	})
+	c := context.Background()
+	gc := newGitClient(c, map[string]string{}, false, withHTTPClient(hc), withLicenseFiles(true))
+
+	l := &Repository{Host: "github.com", Author: "ribice", Project: "kiss"}
+	if err := gc.GetLicense(c, l); err != nil {
+		t.Fatal(err)
+	}
+	want := []LicenseFile{
+		{Name: "LICENSE", Text: "Permission is hereby granted, free of charge, to any person obtaining a copy of this software", SPDX: "mit"},
+		{Name: "NOTICE.md", Text: "notice"},
+	}
+	if !reflect.DeepEqual(l.LicenseFiles, want) {
+		t.Errorf("LicenseFiles = %+v, want %+v", l.LicenseFiles, want)
+	}
+}
+
+func TestGitHubTaggedLicense(t *testing.T) {
+	const mitText = "UGVybWlzc2lvbiBpcyBoZXJlYnkgZ3JhbnRlZCwgZnJlZSBvZiBjaGFyZ2UsIHRvIGFueSBwZXJzb24gb2J0YWluaW5nIGEgY29weSBvZiB0aGlzIHNvZnR3YXJl"
+	hc := NewMockHTTPClient(map[string]string{
+		"https://api.github.com/repos/ribice/kiss/license":                           `{"path": "LICENSE", "sha": "head", "content": "bGljZW5zZS10ZXh0", "license": {"key": "apache-2.0", "name": "Apache License 2.0"}}`,
+		"https://api.github.com/repos/ribice/kiss/contents/LICENSE?ref=v1.0.0":       `{"type": "file", "sha": "v1", "encoding": "base64", "content": "` + mitText + `"}`,
+		"https://api.github.com/repos/ribice/kiss/contents/LICENSE?ref=sub%2Fv2.1.0": `{"type": "file", "sha": "sub", "encoding": "base64", "content": "` + mitText + `"}`,
+		"https://api.github.com/repos/ribice/kiss/contents/LICENSE?ref=v1.1.0":       `{"type": "file", "sha": "v11", "encoding": "base64", "content": "Q29weXJpZ2h0IDIwMjQgRW1pciBSaWJpYw=="}`,
+	})
+	c := context.Background()
+	gc := newGitClient(c, map[string]string{}, false, withHTTPClient(hc), withTaggedLicenses(true))
+
+	tests := map[string]struct {
+		name, version string
+		headOnly      bool
+		wantLicense   string
+		wantSHA       string
+	}{
+		"license changed since tag": {name: "github.com/ribice/kiss", version: "v1.0.0", wantLicense: "MIT", wantSHA: "v1"},
+		"subdirectory module":       {name: "github.com/ribice/kiss/sub/v2", version: "v2.1.0", wantLicense: "MIT", wantSHA: "sub"},
+		"unclassified tag license":  {name: "github.com/ribice/kiss", version: "v1.1.0", wantLicense: "Apache-2.0", wantSHA: "v11"},
+		"missing tag":               {name: "github.com/ribice/kiss", version: "v0.9.0", wantLicense: "Apache-2.0", wantSHA: "head"},
+		"pseudo-version":            {name: "github.com/ribice/kiss", version: "v0.0.0-20240101000000-abcdefabcdef", wantLicense: "Apache-2.0", wantSHA: "head"},
+		"disabled":                  {name: "github.com/ribice/kiss", version: "v1.0.0", headOnly: true, wantLicense: "Apache-2.0", wantSHA: "head"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			l := &Repository{Name: tt.name, Version: tt.version, Host: "github.com", Author: "ribice", Project: "kiss"}
+			gc := gc
+			if tt.headOnly {
+				gc = newGitClient(c, map[string]string{}, false, withHTTPClient(hc))
+			}
+			if err := gc.GetLicense(c, l); err != nil {
+				t.Fatal(err)
+			}
+			if l.License != tt.wantLicense || l.CommitSHA != tt.wantSHA {
+				t.Errorf("got license %s at %s, want %s at %s", l.License, l.CommitSHA, tt.wantLicense, tt.wantSHA)
+			}
+		})
+	}
+}
+
+func TestGitHubTag(t *testing.T) {
+	tests := map[string]struct {
+		name, version string
+		want          string
+	}{
+		"release":         {name: "github.com/a/b", version: "v1.2.3", want: "v1.2.3"},
+		"pre-release":     {name: "github.com/a/b", version: "v1.2.3-rc.1", want: "v1.2.3-rc.1"},
+		"incompatible":    {name: "github.com/a/b", version: "v2.0.0+incompatible", want: "v2.0.0"},
+		"major version":   {name: "github.com/a/b/v3", version: "v3.1.0", want: "v3.1.0"},
+		"subdirectory":    {name: "github.com/a/b/c/v2", version: "v2.0.1", want: "c/v2.0.1"},
+		"pseudo-version":  {name: "github.com/a/b", version: "v0.0.0-20240101000000-abcdefabcdef", want: ""},
+		"partial version": {name: "github.com/a/b", version: "v1.2", want: ""},
+		"empty":           {name: "github.com/a/b"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := githubTag(&Repository{Name: tt.name, Version: tt.version, Author: "a", Project: "b"}); got != tt.want {
+				t.Errorf("githubTag() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPkgGoDevCollectorFactory(t *testing.T) {
+	fixture := mockTransport{
+		"https://pkg.go.dev/example.com/kiss": `<!DOCTYPE html><html><body>
+			<span data-test-id="UnitHeader-version"><a href="?tab=versions">Version: v1.1.0</a></span>
+			<span data-test-id="UnitHeader-licenses"><a href="/example.com/kiss?tab=licenses">BSD-3-Clause</a></span>
+			<div class="UnitMeta-repo"><a href="https://github.com/example/kiss">github.com/example/kiss</a></div>
+		</body></html>`,
+	}
+	c := context.Background()
+	gc := newGitClient(c, map[string]string{}, false, withCollectorFactory(func() *colly.Collector {
+		cl := newCollector(time.Second)
+		cl.WithTransport(fixture)
+		return cl
+	}))
+
+	l := &Repository{Name: "example.com/kiss", Version: "v1.0.0", Host: "example.com"}
+	if err := gc.GetLicense(c, l); err != nil {
+		t.Fatal(err)
+	}
+	want := &Repository{
+		Name:          "example.com/kiss",
+		Version:       "v1.0.0",
+		LatestVersion: "v1.1.0",
+		IsOutdated:    true,
+		Host:          "example.com",
+		Project:       "github.com/example/kiss",
+		License:       "BSD-3-Clause",
+		LicenseURL:    "https://pkg.go.dev/example.com/kiss?tab=licenses",
+		LicenseSPDX:   "BSD-3-Clause",
+		Shortname:     "BSD-3-Clause",
+	}
+	classifyLicense(want)
+	if !reflect.DeepEqual(l, want) {
+		t.Errorf("GetLicense() = %+v, want %+v", l, want)
+	}
+}
+
+func TestPkgGoDevTruncatedVersion(t *testing.T) {
+	fixture := mockTransport{
+		"https://pkg.go.dev/example.com/kiss": `<!DOCTYPE html><html><body>
+			<span data-test-id="UnitHeader-version"><a href="?tab=versions">v1</a></span>
+		</body></html>`,
+	}
+	c := context.Background()
+	gc := newGitClient(c, map[string]string{}, false, withCollectorFactory(func() *colly.Collector {
+		cl := newCollector(time.Second)
+		cl.WithTransport(fixture)
+		return cl
+	}))
+
+	l := &Repository{Name: "example.com/kiss", Version: "v1.0.0", Host: "example.com"}
+	if err := gc.GetLicense(c, l); err == nil {
+		t.Error("expected error for truncated version header")
+	}
+	if l.LatestVersion != "" || l.IsOutdated {
+		t.Errorf("expected latest version not to be set, got %q", l.LatestVersion)
+	}
+}
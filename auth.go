@@ -0,0 +1,134 @@
+package glice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+)
+
+// ErrNoOAuthClientID is returned when interactive authentication is enabled without GitHub OAuth
+// app client ID set in GLICE_GITHUB_CLIENT_ID env variable
+var ErrNoOAuthClientID = errors.New("cannot use interactive authentication without GLICE_GITHUB_CLIENT_ID env variable")
+
+// githubOAuthEndpoint is endpoint of GitHub OAuth2 device flow
+var githubOAuthEndpoint = github.Endpoint
+
+// tokenCacheFile is path of file in home directory GitHub token obtained with device flow is cached in
+var tokenCacheFile = filepath.Join(".glice", "token.json")
+
+// apiKeysFor returns API keys of hosts, see gitKeys. With WithInteractiveAuth, GitHub token is obtained
+// with OAuth2 device flow when GitHub API key isn't set.
+func (c *Client) apiKeysFor(thanks bool) (map[string]string, error) {
+	keys := c.gitKeys()
+	if !c.interactiveAuth || c.dryRun || keys["github.com"] != "" {
+		return keys, nil
+	}
+	tok, err := c.interactiveGitHubToken(context.Background(), thanks, os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("could not authenticate with GitHub: %w", err)
+	}
+	keys["github.com"] = tok
+	return keys, nil
+}
+
+// interactiveGitHubToken returns GitHub token cached in ~/.glice/token.json, or obtains new one with
+// OAuth2 device flow, printing verification URL and code to w. Starring repositories requires
+// public_repo scope, so cached token isn't used for it unless it was granted.
+func (c *Client) interactiveGitHubToken(ctx context.Context, star bool, w io.Writer) (string, error) {
+	var scopes []string
+	if star {
+		scopes = []string{"public_repo"}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	cachePath := filepath.Join(home, tokenCacheFile)
+	if tok, err := readToken(cachePath); err == nil && tok.Valid() && hasScopes(tok, scopes) {
+		return tok.AccessToken, nil
+	}
+
+	clientID := os.Getenv("GLICE_GITHUB_CLIENT_ID")
+	if clientID == "" {
+		return "", ErrNoOAuthClientID
+	}
+	if c.httpClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, c.httpClient)
+	}
+	cfg := &oauth2.Config{ClientID: clientID, Endpoint: githubOAuthEndpoint, Scopes: scopes}
+	tok, err := deviceFlowToken(ctx, cfg, w)
+	if err != nil {
+		return "", err
+	}
+	if err := writeToken(cachePath, tok); err != nil {
+		c.log().Warn("Could not cache GitHub token", "path", cachePath, "error", err)
+	}
+	return tok.AccessToken, nil
+}
+
+// deviceFlowToken runs OAuth2 device flow, polling token endpoint until user enters the code
+// printed to w, or the code expires
+func deviceFlowToken(ctx context.Context, cfg *oauth2.Config, w io.Writer) (*oauth2.Token, error) {
+	da, err := cfg.DeviceAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fmt.Fprintf(w, "To authenticate with GitHub, open %s and enter code %s\n", da.VerificationURI, da.UserCode); err != nil {
+		return nil, err
+	}
+	return cfg.DeviceAccessToken(ctx, da)
+}
+
+// cachedToken is oauth2.Token with scopes it was granted, which oauth2.Token doesn't marshal
+type cachedToken struct {
+	*oauth2.Token
+	Scope string `json:"scope,omitempty"`
+}
+
+func readToken(path string) (*oauth2.Token, error) {
+	bts, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var ct cachedToken
+	if err := json.Unmarshal(bts, &ct); err != nil {
+		return nil, err
+	}
+	if ct.Token == nil {
+		return nil, fmt.Errorf("no token in %s", path)
+	}
+	return ct.Token.WithExtra(map[string]interface{}{"scope": ct.Scope}), nil
+}
+
+// writeToken caches tok readable only by the current user, as it grants access to their GitHub account
+func writeToken(path string, tok *oauth2.Token) error {
+	scope, _ := tok.Extra("scope").(string)
+	bts, err := json.Marshal(cachedToken{Token: tok, Scope: scope})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, bts, 0600)
+}
+
+// hasScopes reports whether tok was granted all scopes. GitHub separates granted scopes with commas.
+func hasScopes(tok *oauth2.Token, scopes []string) bool {
+	granted, _ := tok.Extra("scope").(string)
+	grantedScopes := strings.FieldsFunc(granted, func(r rune) bool { return r == ',' || r == ' ' })
+	for _, s := range scopes {
+		if !containsFold(grantedScopes, s) {
+			return false
+		}
+	}
+	return true
+}
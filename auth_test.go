@@ -0,0 +1,104 @@
+package glice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestClient_InteractiveGitHubToken(t *testing.T) {
+	var tokenRequests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("client_id") != "client" {
+			t.Errorf("unexpected request form %v", r.Form)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/login/device/code":
+			w.Write([]byte(`{"device_code": "device", "user_code": "ABCD-1234", "verification_uri": "https://github.com/login/device", "expires_in": 60, "interval": 1}`))
+		case "/login/oauth/access_token":
+			tokenRequests++
+			if tokenRequests == 1 {
+				w.Write([]byte(`{"error": "authorization_pending"}`))
+				return
+			}
+			w.Write([]byte(`{"access_token": "token", "token_type": "bearer", "scope": "` + r.Form.Get("scope") + `"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	defer func(e oauth2.Endpoint) { githubOAuthEndpoint = e }(githubOAuthEndpoint)
+	githubOAuthEndpoint = oauth2.Endpoint{DeviceAuthURL: srv.URL + "/login/device/code", TokenURL: srv.URL + "/login/oauth/access_token"}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GLICE_GITHUB_CLIENT_ID", "")
+
+	c := &Client{httpClient: srv.Client()}
+	ctx := context.Background()
+	if _, err := c.interactiveGitHubToken(ctx, false, &bytes.Buffer{}); !errors.Is(err, ErrNoOAuthClientID) {
+		t.Fatalf("expected ErrNoOAuthClientID, got %v", err)
+	}
+
+	t.Setenv("GLICE_GITHUB_CLIENT_ID", "client")
+	var prompt bytes.Buffer
+	tok, err := c.interactiveGitHubToken(ctx, false, &prompt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok != "token" {
+		t.Errorf("expected token, got %q", tok)
+	}
+	if !strings.Contains(prompt.String(), "https://github.com/login/device") || !strings.Contains(prompt.String(), "ABCD-1234") {
+		t.Errorf("expected verification URL and code in prompt, got %q", prompt.String())
+	}
+	fi, err := os.Stat(filepath.Join(home, tokenCacheFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Errorf("expected token cache readable only by owner, got %v", fi.Mode())
+	}
+
+	// cached token is reused
+	tokenRequests = 0
+	if tok, err := c.interactiveGitHubToken(ctx, false, &prompt); err != nil || tok != "token" || tokenRequests != 0 {
+		t.Errorf("expected cached token, got %q, %v after %d requests", tok, err, tokenRequests)
+	}
+	// starring needs public_repo scope cached token wasn't granted
+	if _, err := c.interactiveGitHubToken(ctx, true, &prompt); err != nil || tokenRequests == 0 {
+		t.Errorf("expected new token with public_repo scope, got %v after %d requests", err, tokenRequests)
+	}
+	cached, err := readToken(filepath.Join(home, tokenCacheFile))
+	if err != nil || !hasScopes(cached, []string{"public_repo"}) {
+		t.Errorf("expected cached token with public_repo scope, got %+v, %v", cached, err)
+	}
+}
+
+func TestClient_APIKeysForInteractiveAuth(t *testing.T) {
+	t.Setenv("GITHUB_API_KEY", "key")
+	c := &Client{interactiveAuth: true}
+	keys, err := c.apiKeysFor(false)
+	if err != nil || keys["github.com"] != "key" {
+		t.Errorf("expected API key to be used without device flow, got %v, %v", keys, err)
+	}
+
+	t.Setenv("GITHUB_API_KEY", "")
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GLICE_GITHUB_CLIENT_ID", "")
+	if _, err := c.apiKeysFor(false); !errors.Is(err, ErrNoOAuthClientID) {
+		t.Errorf("expected ErrNoOAuthClientID, got %v", err)
+	}
+	c.dryRun = true
+	if _, err := c.apiKeysFor(false); err != nil {
+		t.Errorf("expected dry run not to authenticate, got %v", err)
+	}
+}
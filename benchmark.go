@@ -0,0 +1,99 @@
+package glice
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// HostLatency holds statistics of license fetching latency of dependencies hosted on Host
+type HostLatency struct {
+	Host  string
+	Count int
+	Min   time.Duration
+	Max   time.Duration
+	Mean  time.Duration
+	// P95 is latency 95% of license requests to Host didn't exceed
+	P95 time.Duration
+}
+
+// Benchmark parses direct dependencies and returns how long fetching license of each of them took,
+// keyed by import path. Dependencies read from cache or vendor directory aren't fetched, so they're
+// missing from the result. HostLatencies aggregates the latencies per host, which helps finding
+// the bottleneck when tuning WithConcurrency and WithTimeout. License requests are cancelled once
+// ctx is done.
+func (c *Client) Benchmark(ctx context.Context) (map[string]time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := c.parseDependencies(ctx, false, false); err != nil {
+		return nil, err
+	}
+	return c.latencies, ctx.Err()
+}
+
+// HostLatencies returns latency statistics of the last license fetching per host, sorted by mean
+// latency with the slowest host first
+func (c *Client) HostLatencies() []HostLatency {
+	byHost := map[string][]time.Duration{}
+	for _, d := range c.dependencies {
+		if l, ok := c.latencies[d.Name]; ok {
+			h := c.fetchHost(d)
+			byHost[h] = append(byHost[h], l)
+		}
+	}
+
+	stats := make([]HostLatency, 0, len(byHost))
+	for h, ls := range byHost {
+		sort.Slice(ls, func(i, j int) bool { return ls[i] < ls[j] })
+		var total time.Duration
+		for _, l := range ls {
+			total += l
+		}
+		stats = append(stats, HostLatency{
+			Host:  h,
+			Count: len(ls),
+			Min:   ls[0],
+			Max:   ls[len(ls)-1],
+			Mean:  total / time.Duration(len(ls)),
+			// nearest-rank percentile
+			P95: ls[(len(ls)*95+99)/100-1],
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Mean != stats[j].Mean {
+			return stats[i].Mean > stats[j].Mean
+		}
+		return stats[i].Host < stats[j].Host
+	})
+	return stats
+}
+
+// fetchHost returns host license of r is fetched from. Dependencies of hosts without supported API,
+// including vanity import paths resolved to such hosts, are scraped from pkg.go.dev.
+func (c *Client) fetchHost(r *Repository) string {
+	switch {
+	case r.Host == "gitea":
+		return r.HostURL
+	case r.Host == "github.com", r.Host == "gitlab.com", r.Host == "bitbucket.org", containsFold(c.gitlabHosts, r.Host):
+		return r.Host
+	}
+	return "pkg.go.dev"
+}
+
+func (c *Client) printBenchmark(writeTo io.Writer) {
+	tw := tablewriter.NewWriter(writeTo)
+	tw.SetHeader([]string{"Host", "Dependencies", "Min", "Max", "Mean", "P95"})
+	for _, s := range c.HostLatencies() {
+		tw.Append([]string{s.Host, strconv.Itoa(s.Count), formatLatency(s.Min), formatLatency(s.Max), formatLatency(s.Mean), formatLatency(s.P95)})
+	}
+	tw.Render()
+}
+
+func formatLatency(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
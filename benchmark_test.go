@@ -0,0 +1,107 @@
+package glice
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_HostLatencies(t *testing.T) {
+	c := &Client{
+		dependencies: []*Repository{
+			{Name: "github.com/a/a", Host: "github.com"},
+			{Name: "github.com/b/b", Host: "github.com"},
+			{Name: "github.com/c/c", Host: "github.com"},
+			{Name: "codeberg.org/d/d", Host: "gitea", HostURL: "codeberg.org"},
+			{Name: "example.com/e", Host: ""},
+			{Name: "example.com/cached", Host: ""},
+		},
+		latencies: map[string]time.Duration{
+			"github.com/a/a":   100 * time.Millisecond,
+			"github.com/b/b":   300 * time.Millisecond,
+			"github.com/c/c":   200 * time.Millisecond,
+			"codeberg.org/d/d": time.Second,
+			"example.com/e":    50 * time.Millisecond,
+		},
+	}
+	want := []HostLatency{
+		{Host: "codeberg.org", Count: 1, Min: time.Second, Max: time.Second, Mean: time.Second, P95: time.Second},
+		{Host: "github.com", Count: 3, Min: 100 * time.Millisecond, Max: 300 * time.Millisecond, Mean: 200 * time.Millisecond, P95: 300 * time.Millisecond},
+		{Host: "pkg.go.dev", Count: 1, Min: 50 * time.Millisecond, Max: 50 * time.Millisecond, Mean: 50 * time.Millisecond, P95: 50 * time.Millisecond},
+	}
+	if got := c.HostLatencies(); !reflect.DeepEqual(got, want) {
+		t.Errorf("HostLatencies() = %+v, want %+v", got, want)
+	}
+
+	c.format = "benchmark"
+	var buf bytes.Buffer
+	if err := c.PrintContext(context.Background(), &buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, s := range []string{"HOST", "P95", "| github.com   |            3 | 100ms | 300ms | 200ms | 300ms |"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("expected %q in output:\n%s", s, out)
+		}
+	}
+	if strings.Index(out, "codeberg.org") > strings.Index(out, "github.com") {
+		t.Errorf("expected slowest host first:\n%s", out)
+	}
+}
+
+func TestClient_Benchmark(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/a\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewClient(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.Benchmark(context.Background())
+	if err != nil || got == nil || len(got) != 0 {
+		t.Errorf("Benchmark() = %v, %v, want empty latencies", got, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.Benchmark(ctx); err != context.Canceled {
+		t.Errorf("Benchmark() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+// blockingTransport waits until request is cancelled
+type blockingTransport struct{}
+
+func (blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestClient_BenchmarkDeadline(t *testing.T) {
+	dir := t.TempDir()
+	gomod := "module example.com/a\n\ngo 1.21\n\nrequire github.com/ribice/kiss v1.0.0\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewClient(dir, WithHTTPClient(&http.Client{Transport: blockingTransport{}}), WithTimeout(time.Minute), WithProgress(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := c.Benchmark(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Benchmark() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("expected license requests to be cancelled with ctx, Benchmark took %v", d)
+	}
+}
@@ -0,0 +1,69 @@
+package glice
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// diskCache stores fetched licenses as JSON files, so subsequent runs don't have to fetch them again
+type diskCache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// cacheEntry exposes fields of Repository that are hidden from JSON output
+type cacheEntry struct {
+	*Repository
+	Shortname string `json:"shortname,omitempty"`
+	Text      string `json:"text,omitempty"`
+}
+
+// path of the cached repository, it includes version so version bump is a cache miss
+func (dc *diskCache) path(r *Repository) string {
+	name := filepath.Join(r.Author, r.Project)
+	if r.Author == "" || r.Project == "" {
+		name = filepath.FromSlash(r.Name)
+	}
+	return filepath.Join(dc.dir, r.Host, name+"-"+r.Version+".json")
+}
+
+// get populates r from cache, returning false if it's missing or older than ttl
+func (dc *diskCache) get(r *Repository) bool {
+	p := dc.path(r)
+	fi, err := os.Stat(p)
+	if err != nil {
+		return false
+	}
+	if dc.ttl > 0 && dc.now().Sub(fi.ModTime()) > dc.ttl {
+		return false
+	}
+
+	bts, err := os.ReadFile(p)
+	if err != nil {
+		return false
+	}
+
+	entry := cacheEntry{Repository: &Repository{}}
+	if err := json.Unmarshal(bts, &entry); err != nil {
+		return false
+	}
+	*r = *entry.Repository
+	r.Shortname, r.Text = entry.Shortname, entry.Text
+	return true
+}
+
+// put stores r under the given path, which has to be computed before fetching
+// as fetching can change repository version.
+func (dc *diskCache) put(p string, r *Repository) error {
+	bts, err := json.Marshal(cacheEntry{Repository: r, Shortname: r.Shortname, Text: r.Text})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(p, bts, 0644)
+}
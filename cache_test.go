@@ -0,0 +1,85 @@
+package glice
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiskCache(t *testing.T) {
+	now := time.Now()
+	dc := &diskCache{dir: t.TempDir(), ttl: time.Hour, now: func() time.Time { return now }}
+
+	r := &Repository{Name: "github.com/ribice/glice", Host: "github.com", Author: "ribice", Project: "glice", Version: "v1.0.0"}
+	if got := dc.path(r); got != filepath.Join(dc.dir, "github.com", "ribice", "glice-v1.0.0.json") {
+		t.Errorf("unexpected cache path: %s", got)
+	}
+	if dc.get(&Repository{Name: r.Name, Host: r.Host, Author: r.Author, Project: r.Project, Version: r.Version}) {
+		t.Fatal("expected cache miss for empty cache")
+	}
+
+	r.License, r.Shortname, r.Text = "MIT", "MIT", "bGljZW5zZS10ZXh0"
+	if err := dc.put(dc.path(r), r); err != nil {
+		t.Fatal(err)
+	}
+
+	got := &Repository{Name: r.Name, Host: r.Host, Author: r.Author, Project: r.Project, Version: r.Version}
+	if !dc.get(got) {
+		t.Fatal("expected cache hit")
+	}
+	if !reflect.DeepEqual(got, r) {
+		t.Errorf("get() = %+v, want %+v", got, r)
+	}
+
+	bumped := &Repository{Name: r.Name, Host: r.Host, Author: r.Author, Project: r.Project, Version: "v1.1.0"}
+	if dc.get(bumped) {
+		t.Error("expected cache miss after version bump")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if dc.get(&Repository{Name: r.Name, Host: r.Host, Author: r.Author, Project: r.Project, Version: r.Version}) {
+		t.Error("expected cache miss for expired entry")
+	}
+
+	dc.ttl = 0
+	if !dc.get(&Repository{Name: r.Name, Host: r.Host, Author: r.Author, Project: r.Project, Version: r.Version}) {
+		t.Error("expected entries to never expire with zero ttl")
+	}
+}
+
+func TestDiskCache_PathWithoutAuthor(t *testing.T) {
+	dc := &diskCache{dir: "cache"}
+	r := &Repository{Name: "golang.org/x/mod", Host: "pkg.go.dev", Version: "v0.20.0"}
+	if got, want := dc.path(r), filepath.Join("cache", "pkg.go.dev", "golang.org", "x", "mod-v0.20.0.json"); got != want {
+		t.Errorf("path() = %s, want %s", got, want)
+	}
+}
+
+func TestClient_ParseDependenciesCached(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewClient(wd(), WithCache(dir, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repos, err := ListRepositories(wd(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range repos {
+		r.License = "MIT"
+		if err := c.cache.put(c.cache.path(r), r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := c.ParseDependencies(false, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range c.dependencies {
+		if d.License != "MIT" {
+			t.Errorf("expected license of %s to be read from cache", d.Name)
+		}
+	}
+}
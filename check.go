@@ -0,0 +1,306 @@
+package glice
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/ribice/glice/v2/mod"
+)
+
+// ErrLicenseViolation is returned when a dependency uses a license that is not in the allowlist
+var ErrLicenseViolation = errors.New("dependencies with disallowed licenses found")
+
+// ErrCopyleftFound is returned when dependencies use strong or network copyleft (e.g. GPL, AGPL) license
+var ErrCopyleftFound = errors.New("dependencies with copyleft licenses found")
+
+// ErrUnrequiredGoSum is returned when go.sum has module hashes that no go.mod require directive needs,
+// which can mean go.sum was edited manually or is corrupt
+var ErrUnrequiredGoSum = errors.New("go.sum entries without go.mod require directive found")
+
+// ErrBlockedLicense is returned when dependencies use a license from the blocklist
+type ErrBlockedLicense struct {
+	Repos []*Repository
+}
+
+func (e ErrBlockedLicense) Error() string {
+	pkgs := make([]string, len(e.Repos))
+	for i, r := range e.Repos {
+		pkgs[i] = fmt.Sprintf("%s (%s)", r.Name, r.License)
+	}
+	return "dependencies with blocked licenses found: " + strings.Join(pkgs, ", ")
+}
+
+// ErrUnknownLicense is returned when dependencies have no license or one that couldn't be identified.
+// It wraps an error per dependency, naming its import path.
+type ErrUnknownLicense struct {
+	Repos []*Repository
+}
+
+func (e ErrUnknownLicense) Error() string {
+	pkgs := make([]string, len(e.Repos))
+	for i, r := range e.Repos {
+		pkgs[i] = r.Name
+	}
+	return "dependencies with unknown licenses found: " + strings.Join(pkgs, ", ")
+}
+
+func (e ErrUnknownLicense) Unwrap() []error {
+	errs := make([]error, len(e.Repos))
+	for i, r := range e.Repos {
+		errs[i] = fmt.Errorf("%s: unknown license", r.Name)
+	}
+	return errs
+}
+
+// CheckMode configures license checks done by PrintTo once dependencies are parsed
+type CheckMode struct {
+	// Allowed overrides Client.AllowedLicenses when not empty
+	Allowed []string
+	// Blocked overrides Client.BlockedLicenses when not empty
+	Blocked []string
+	// WarnOnly logs violations instead of returning an error
+	WarnOnly bool
+}
+
+// CheckAllowed returns import paths of dependencies whose license is not in AllowedLicenses.
+// Comparison is case-insensitive and an empty allowlist allows everything.
+func (c *Client) CheckAllowed() ([]string, error) {
+	if len(c.AllowedLicenses) < 1 {
+		return nil, nil
+	}
+
+	var violations []string
+	for _, d := range c.dependencies {
+		if !containsFold(c.AllowedLicenses, d.License) {
+			violations = append(violations, d.Name)
+		}
+	}
+
+	if len(violations) > 0 {
+		return violations, ErrLicenseViolation
+	}
+	return nil, nil
+}
+
+// CheckBlocked returns dependencies whose license is in BlockedLicenses.
+// Comparison is case-insensitive.
+func (c *Client) CheckBlocked() ([]*Repository, error) {
+	var blocked []*Repository
+	for _, d := range c.dependencies {
+		if containsFold(c.BlockedLicenses, d.License) {
+			blocked = append(blocked, d)
+		}
+	}
+
+	if len(blocked) > 0 {
+		return blocked, ErrBlockedLicense{Repos: blocked}
+	}
+	return nil, nil
+}
+
+// Check runs both allowlist and blocklist checks in a single pass over dependencies.
+// Blocklist violations take precedence over allowlist ones in the returned error.
+func (c *Client) Check(mode CheckMode) error {
+	allowed, blocked := c.AllowedLicenses, c.BlockedLicenses
+	if len(mode.Allowed) > 0 {
+		allowed = mode.Allowed
+	}
+	if len(mode.Blocked) > 0 {
+		blocked = mode.Blocked
+	}
+
+	var notAllowed []string
+	var blockedRepos []*Repository
+	for _, d := range c.dependencies {
+		if containsFold(blocked, d.License) {
+			blockedRepos = append(blockedRepos, d)
+		}
+		if len(allowed) > 0 && !containsFold(allowed, d.License) {
+			notAllowed = append(notAllowed, d.Name)
+		}
+	}
+
+	var err error
+	switch {
+	case len(blockedRepos) > 0:
+		err = ErrBlockedLicense{Repos: blockedRepos}
+	case len(notAllowed) > 0:
+		err = fmt.Errorf("%w: %s", ErrLicenseViolation, strings.Join(notAllowed, ", "))
+	}
+
+	if err != nil && mode.WarnOnly {
+		c.log().Warn("Dependencies violate license policy", "error", err)
+		return nil
+	}
+	return err
+}
+
+// CheckGPLContamination returns dependencies with strong or network copyleft licenses, that
+// usually require the whole project to be distributed under the same license.
+func (c *Client) CheckGPLContamination() ([]*Repository, error) {
+	copyleft := c.FilterByCategory(CategoryStrongCopyleft, CategoryNetworkCopyleft)
+	if len(copyleft) < 1 {
+		return nil, nil
+	}
+
+	pkgs := make([]string, len(copyleft))
+	for i, r := range copyleft {
+		pkgs[i] = fmt.Sprintf("%s (%s)", r.Name, r.License)
+	}
+	return copyleft, fmt.Errorf("%w: %s", ErrCopyleftFound, strings.Join(pkgs, ", "))
+}
+
+// warnCopyleft prints a warning about copyleft dependencies to stderr, returning the error if
+// client is configured to fail on them
+func (c *Client) warnCopyleft() error {
+	_, err := c.CheckGPLContamination()
+	if err == nil {
+		return nil
+	}
+	color.New(color.FgHiRed, color.Bold).Fprintf(os.Stderr, "WARNING: %v\n", err)
+	if c.failOnCopyleft {
+		return err
+	}
+	return nil
+}
+
+// checkUnknown returns ErrUnknownLicense if client is configured to fail on unknown licenses
+// and any dependency has one
+func (c *Client) checkUnknown() error {
+	if !c.failOnUnknown {
+		return nil
+	}
+	if unknown := c.FilterUnknown(); len(unknown) > 0 {
+		return ErrUnknownLicense{Repos: unknown}
+	}
+	return nil
+}
+
+// VerifyGoSum checks that every module hash in go.sum belongs to a module required by go.mod.
+// Note that go mod tidy also keeps hashes of modules needed only by tests of dependencies,
+// so reported entries should be reviewed rather than treated as corruption.
+func (c *Client) VerifyGoSum() error {
+	return verifyGoSum(c.path)
+}
+
+func verifyGoSum(path string) error {
+	sums, err := mod.ParseGoSum(path)
+	if err != nil {
+		return err
+	}
+	deps, err := mod.Parse(path, true)
+	if err != nil {
+		return err
+	}
+
+	required := map[string]bool{}
+	for _, d := range deps {
+		required[d.Path+"@"+d.Version] = true
+	}
+	var unrequired []string
+	for m := range sums {
+		if !required[m] {
+			unrequired = append(unrequired, m)
+		}
+	}
+
+	if len(unrequired) > 0 {
+		sort.Strings(unrequired)
+		return fmt.Errorf("%w: %s", ErrUnrequiredGoSum, strings.Join(unrequired, ", "))
+	}
+	return nil
+}
+
+// FilterByCategory returns dependencies whose license belongs to any of the categories
+func (c *Client) FilterByCategory(cats ...string) []*Repository {
+	var filtered []*Repository
+	for _, d := range c.dependencies {
+		cat := d.Category
+		if cat == "" {
+			cat = LicenseCategory(d.License)
+		}
+		if containsFold(cats, cat) {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterByLicense returns dependencies whose license or its SPDX identifier matches any of the
+// patterns, which can be exact identifiers or glob patterns like "GPL*". Matching is case-insensitive.
+func (c *Client) FilterByLicense(patterns ...string) []*Repository {
+	lower := make([]string, len(patterns))
+	for i, p := range patterns {
+		lower[i] = strings.ToLower(p)
+	}
+
+	var filtered []*Repository
+	for _, d := range c.dependencies {
+		if matchAny(lower, strings.ToLower(d.License)) || matchAny(lower, strings.ToLower(spdxLicense(d))) {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}
+
+// FilterByHost returns dependencies hosted on any of the hosts, e.g. github.com. Matching is case-insensitive.
+func (c *Client) FilterByHost(hosts ...string) []*Repository {
+	var filtered []*Repository
+	for _, d := range c.dependencies {
+		if containsFold(hosts, d.Host) {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}
+
+// FilterUnknown returns dependencies without license or with license that couldn't be identified
+func (c *Client) FilterUnknown() []*Repository {
+	var filtered []*Repository
+	for _, d := range c.dependencies {
+		if isUnknownLicense(d) {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}
+
+// isUnknownLicense reports whether r has no license, license that couldn't be identified or
+// wasn't fetched because its host is unreachable
+func isUnknownLicense(r *Repository) bool {
+	return r.License == "" || strings.EqualFold(r.License, "other") || r.License == unreachableLicense
+}
+
+// CheckAbandoned returns archived dependencies and dependencies that weren't pushed to for more years
+// than set with WithAbandonedThreshold. Archived dependencies don't get security fixes anymore,
+// which is especially risky for copyleft ones that can't easily be replaced by a fork.
+// Only GitHub repositories are checked, and only when client was created with WithAbandonedThreshold.
+func (c *Client) CheckAbandoned() []*Repository {
+	var cutoff time.Time
+	if c.abandonedYears > 0 {
+		cutoff = time.Now().AddDate(-c.abandonedYears, 0, 0)
+	}
+
+	var abandoned []*Repository
+	for _, d := range c.dependencies {
+		if d.IsArchived || !cutoff.IsZero() && d.PushedAt != nil && d.PushedAt.Before(cutoff) {
+			abandoned = append(abandoned, d)
+		}
+	}
+	return abandoned
+}
@@ -0,0 +1,292 @@
+package glice
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_CheckAllowed(t *testing.T) {
+	deps := []*Repository{
+		{Name: "github.com/ribice/glice", License: "MIT"},
+		{Name: "github.com/google/go-github", License: "BSD-3-Clause"},
+		{Name: "github.com/some/gpl", License: "GPL-3.0"},
+	}
+	tests := map[string]struct {
+		allowed []string
+		want    []string
+		wantErr error
+	}{
+		"empty allowlist allows everything": {},
+		"all allowed": {
+			allowed: []string{"MIT", "BSD-3-Clause", "GPL-3.0"},
+		},
+		"case insensitive": {
+			allowed: []string{"mit", "bsd-3-clause"},
+			want:    []string{"github.com/some/gpl"},
+			wantErr: ErrLicenseViolation,
+		},
+		"violations": {
+			allowed: []string{"MIT"},
+			want:    []string{"github.com/google/go-github", "github.com/some/gpl"},
+			wantErr: ErrLicenseViolation,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Client{dependencies: deps, AllowedLicenses: tt.allowed}
+			got, err := c.CheckAllowed()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckAllowed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_CheckBlocked(t *testing.T) {
+	gpl := &Repository{Name: "github.com/some/gpl", License: "GPL-3.0"}
+	deps := []*Repository{{Name: "github.com/ribice/glice", License: "MIT"}, gpl}
+
+	c := &Client{dependencies: deps}
+	if got, err := c.CheckBlocked(); err != nil || got != nil {
+		t.Errorf("CheckBlocked() = %v, %v, want no violations", got, err)
+	}
+
+	c.BlockedLicenses = []string{"gpl-3.0", "AGPL-3.0"}
+	got, err := c.CheckBlocked()
+	var blockedErr ErrBlockedLicense
+	if !errors.As(err, &blockedErr) {
+		t.Fatalf("CheckBlocked() error = %v, want ErrBlockedLicense", err)
+	}
+	if !reflect.DeepEqual(got, []*Repository{gpl}) || !reflect.DeepEqual(blockedErr.Repos, got) {
+		t.Errorf("CheckBlocked() = %v, want %v", got, []*Repository{gpl})
+	}
+	if want := "dependencies with blocked licenses found: github.com/some/gpl (GPL-3.0)"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestClient_Check(t *testing.T) {
+	deps := []*Repository{
+		{Name: "github.com/ribice/glice", License: "MIT"},
+		{Name: "github.com/some/gpl", License: "GPL-3.0"},
+	}
+	tests := map[string]struct {
+		mode    CheckMode
+		wantErr bool
+	}{
+		"no checks": {},
+		"allowed": {
+			mode: CheckMode{Allowed: []string{"MIT", "GPL-3.0"}, Blocked: []string{"AGPL-3.0"}},
+		},
+		"not allowed": {
+			mode:    CheckMode{Allowed: []string{"MIT"}},
+			wantErr: true,
+		},
+		"blocked": {
+			mode:    CheckMode{Blocked: []string{"GPL-3.0"}},
+			wantErr: true,
+		},
+		"warn only": {
+			mode: CheckMode{Allowed: []string{"MIT"}, Blocked: []string{"GPL-3.0"}, WarnOnly: true},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Client{dependencies: deps}
+			if err := c.Check(tt.mode); (err != nil) != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_FilterByCategory(t *testing.T) {
+	mit := &Repository{Name: "github.com/ribice/glice", License: "MIT", Category: CategoryPermissive}
+	gpl := &Repository{Name: "github.com/some/gpl", License: "GPL-3.0"}
+	agpl := &Repository{Name: "github.com/some/agpl", License: "AGPL-3.0", Category: CategoryNetworkCopyleft}
+	c := &Client{dependencies: []*Repository{mit, gpl, agpl}}
+
+	if got := c.FilterByCategory(CategoryStrongCopyleft, CategoryNetworkCopyleft); !reflect.DeepEqual(got, []*Repository{gpl, agpl}) {
+		t.Errorf("FilterByCategory() = %v, want copyleft dependencies", got)
+	}
+	if got := c.FilterByCategory(CategoryPublicDomain); got != nil {
+		t.Errorf("FilterByCategory() = %v, want none", got)
+	}
+}
+
+func TestClient_CheckGPLContamination(t *testing.T) {
+	mit := &Repository{Name: "github.com/ribice/glice", License: "MIT"}
+	lgpl := &Repository{Name: "github.com/some/lgpl", License: "LGPL-3.0"}
+	gpl := &Repository{Name: "github.com/some/gpl", License: "GPL-3.0"}
+	agpl := &Repository{Name: "github.com/some/agpl", License: "AGPL-3.0"}
+
+	c := &Client{dependencies: []*Repository{mit, lgpl}}
+	if got, err := c.CheckGPLContamination(); err != nil || got != nil {
+		t.Errorf("CheckGPLContamination() = %v, %v, want no violations", got, err)
+	}
+	if err := c.warnCopyleft(); err != nil {
+		t.Error(err)
+	}
+
+	c.dependencies = append(c.dependencies, gpl, agpl)
+	got, err := c.CheckGPLContamination()
+	if !errors.Is(err, ErrCopyleftFound) {
+		t.Errorf("CheckGPLContamination() error = %v, want %v", err, ErrCopyleftFound)
+	}
+	if !reflect.DeepEqual(got, []*Repository{gpl, agpl}) {
+		t.Errorf("CheckGPLContamination() = %v, want GPL and AGPL dependencies", got)
+	}
+
+	if err := c.warnCopyleft(); err != nil {
+		t.Errorf("expected only a warning without WithFailOnCopyleft, got %v", err)
+	}
+	WithFailOnCopyleft()(c)
+	if err := c.warnCopyleft(); !errors.Is(err, ErrCopyleftFound) {
+		t.Errorf("warnCopyleft() error = %v, want %v", err, ErrCopyleftFound)
+	}
+}
+
+func TestClient_CheckUnknown(t *testing.T) {
+	mit := &Repository{Name: "github.com/fatih/color", License: "MIT"}
+	none := &Repository{Name: "github.com/some/none"}
+	other := &Repository{Name: "github.com/some/other", License: "Other"}
+
+	c := &Client{dependencies: []*Repository{mit, none, other}}
+	if err := c.checkUnknown(); err != nil {
+		t.Errorf("expected no error without WithFailOnUnknown, got %v", err)
+	}
+
+	WithFailOnUnknown()(c)
+	err := c.checkUnknown()
+	var unknown ErrUnknownLicense
+	if !errors.As(err, &unknown) {
+		t.Fatalf("checkUnknown() error = %v, want ErrUnknownLicense", err)
+	}
+	if !reflect.DeepEqual(unknown.Repos, []*Repository{none, other}) {
+		t.Errorf("expected dependencies without and with Other license, got %v", unknown.Repos)
+	}
+	if want := "dependencies with unknown licenses found: github.com/some/none, github.com/some/other"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+	if errs := unknown.Unwrap(); len(errs) != 2 || errs[1].Error() != "github.com/some/other: unknown license" {
+		t.Errorf("Unwrap() = %v, want error per dependency", errs)
+	}
+
+	c.dependencies = []*Repository{mit}
+	if err := c.checkUnknown(); err != nil {
+		t.Errorf("expected no error without unknown licenses, got %v", err)
+	}
+}
+
+func TestClient_VerifyGoSum(t *testing.T) {
+	gomod := "module example.com/a\n\ngo 1.18\n\nrequire github.com/fatih/color v1.17.0\n"
+	tests := map[string]struct {
+		gosum   string
+		wantErr error
+	}{
+		"valid": {
+			gosum: "github.com/fatih/color v1.17.0 h1:GlRw1BRJxkpqUCBKzKOw098ed57fEsKeNjpTe3cSjK4=\n" +
+				"github.com/mattn/go-colorable v0.1.13/go.mod h1:7S9/ev0klgBDR4GtXTXX8a3vIGJpMovkB8vQcUbaXHg=\n",
+		},
+		"unrequired module": {
+			gosum: "github.com/fatih/color v1.17.0 h1:GlRw1BRJxkpqUCBKzKOw098ed57fEsKeNjpTe3cSjK4=\n" +
+				"github.com/fatih/color v1.16.0 h1:zmkK9Ngbjj+K0yRhTVONQh1p/HknKYSlNT+vZCzyokM=\n",
+			wantErr: ErrUnrequiredGoSum,
+		},
+		"missing go.sum": {
+			wantErr: os.ErrNotExist,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0644)
+			if tt.gosum != "" {
+				os.WriteFile(filepath.Join(dir, "go.sum"), []byte(tt.gosum), 0644)
+			}
+			c := &Client{path: dir}
+			if err := c.VerifyGoSum(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyGoSum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_FilterByLicense(t *testing.T) {
+	mit := &Repository{Name: "github.com/ribice/glice", License: "MIT", Host: "github.com"}
+	gpl2 := &Repository{Name: "github.com/some/gpl2", License: "GPL-2.0", Host: "github.com"}
+	gpl3 := &Repository{Name: "gitlab.com/some/gpl3", License: "GPL-3.0", Host: "gitlab.com"}
+	bsd := &Repository{Name: "example.com/bsd", License: "BSD 3-Clause", LicenseSPDX: "bsd-3-clause", Host: "pkg.go.dev"}
+	c := &Client{dependencies: []*Repository{mit, gpl2, gpl3, bsd}}
+
+	tests := map[string]struct {
+		patterns []string
+		want     []*Repository
+	}{
+		"exact":            {patterns: []string{"MIT"}, want: []*Repository{mit}},
+		"case insensitive": {patterns: []string{"mit", "gpl-3.0"}, want: []*Repository{mit, gpl3}},
+		"glob":             {patterns: []string{"GPL*"}, want: []*Repository{gpl2, gpl3}},
+		"spdx identifier":  {patterns: []string{"BSD-3-Clause"}, want: []*Repository{bsd}},
+		"no match":         {patterns: []string{"AGPL*"}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := c.FilterByLicense(tt.patterns...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FilterByLicense() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := c.FilterByHost("GitHub.com"); !reflect.DeepEqual(got, []*Repository{mit, gpl2}) {
+		t.Errorf("FilterByHost() = %v, want GitHub dependencies", got)
+	}
+	if len(c.dependencies) != 4 {
+		t.Errorf("expected dependencies not to be mutated, got %v", c.dependencies)
+	}
+}
+
+func TestClient_FilterUnknown(t *testing.T) {
+	mit := &Repository{Name: "github.com/ribice/glice", License: "MIT"}
+	other := &Repository{Name: "github.com/some/other", License: "Other"}
+	none := &Repository{Name: "golang.org/x/mod"}
+	c := &Client{dependencies: []*Repository{mit, other, none}}
+
+	if got := c.FilterUnknown(); !reflect.DeepEqual(got, []*Repository{other, none}) {
+		t.Errorf("FilterUnknown() = %v, want dependencies without known license", got)
+	}
+}
+
+func TestClient_CheckAbandoned(t *testing.T) {
+	recent := time.Now().AddDate(0, -6, 0)
+	old := time.Now().AddDate(-3, 0, 0)
+	active := &Repository{Name: "github.com/ribice/glice", PushedAt: &recent}
+	stale := &Repository{Name: "github.com/some/stale", PushedAt: &old}
+	archived := &Repository{Name: "github.com/some/archived", IsArchived: true, ArchivedAt: &recent, PushedAt: &recent}
+	unknown := &Repository{Name: "gitlab.com/some/project"}
+	deps := []*Repository{active, stale, archived, unknown}
+
+	tests := map[string]struct {
+		years int
+		want  []*Repository
+	}{
+		"archived only": {years: 0, want: []*Repository{archived}},
+		"two years":     {years: 2, want: []*Repository{stale, archived}},
+		"five years":    {years: 5, want: []*Repository{archived}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Client{dependencies: deps}
+			WithAbandonedThreshold(tt.years)(c)
+			if got := c.CheckAbandoned(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckAbandoned() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package glice
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrHostUnreachable is returned for dependencies whose license wasn't fetched because their host
+// failed too many consecutive requests, see WithCircuitBreaker. Such dependencies get unreachableLicense.
+var ErrHostUnreachable = errors.New("host unreachable")
+
+// unreachableLicense is set as license of dependencies skipped because their host is unreachable
+const unreachableLicense = "UNREACHABLE"
+
+// circuitBreaker stops requests to a host after threshold consecutive failures. Once openDuration
+// passes, single request is allowed again, closing the breaker on success.
+type circuitBreaker struct {
+	mu           sync.Mutex
+	threshold    int
+	openDuration time.Duration
+	failures     int
+	openedAt     time.Time
+	now          func() time.Time
+}
+
+func newCircuitBreaker(threshold int, openDuration time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, openDuration: openDuration, now: time.Now}
+}
+
+// Allow reports whether request to the host may be sent
+func (cb *circuitBreaker) Allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.failures < cb.threshold {
+		return true
+	}
+	if now := cb.now(); now.Sub(cb.openedAt) >= cb.openDuration {
+		// other requests are rejected until this one finishes or openDuration passes again
+		cb.openedAt = now
+		return true
+	}
+	return false
+}
+
+// RecordFailure counts failed request, opening the breaker once threshold is reached
+func (cb *circuitBreaker) RecordFailure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.failures++
+	if cb.failures >= cb.threshold {
+		cb.openedAt = cb.now()
+	}
+}
+
+// RecordSuccess closes the breaker
+func (cb *circuitBreaker) RecordSuccess() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.failures = 0
+}
+
+// hostBreakers holds circuit breaker per host, creating them on first use
+type hostBreakers struct {
+	mu           sync.Mutex
+	threshold    int
+	openDuration time.Duration
+	breakers     map[string]*circuitBreaker
+}
+
+// newHostBreakers returns nil when threshold isn't positive, which disables circuit breaking
+func newHostBreakers(threshold int, openDuration time.Duration) *hostBreakers {
+	if threshold < 1 {
+		return nil
+	}
+	return &hostBreakers{threshold: threshold, openDuration: openDuration, breakers: map[string]*circuitBreaker{}}
+}
+
+func (hb *hostBreakers) get(host string) *circuitBreaker {
+	if hb == nil {
+		return nil
+	}
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+	cb, ok := hb.breakers[host]
+	if !ok {
+		cb = newCircuitBreaker(hb.threshold, hb.openDuration)
+		hb.breakers[host] = cb
+	}
+	return cb
+}
@@ -0,0 +1,143 @@
+package glice
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cb := newCircuitBreaker(2, time.Minute)
+	cb.now = func() time.Time { return now }
+
+	steps := []struct {
+		name      string
+		do        func()
+		wantAllow bool
+	}{
+		{name: "closed", wantAllow: true},
+		{name: "one failure", do: cb.RecordFailure, wantAllow: true},
+		{name: "success resets failures", do: func() { cb.RecordSuccess(); cb.RecordFailure() }, wantAllow: true},
+		{name: "threshold reached", do: cb.RecordFailure, wantAllow: false},
+		{name: "still open", do: func() { now = now.Add(30 * time.Second) }, wantAllow: false},
+		{name: "trial request", do: func() { now = now.Add(30 * time.Second) }, wantAllow: true},
+		{name: "only single trial request", wantAllow: false},
+		{name: "trial failed", do: func() { cb.RecordFailure(); now = now.Add(59 * time.Second) }, wantAllow: false},
+		{name: "trial succeeded", do: func() { now = now.Add(time.Second); cb.Allow(); cb.RecordSuccess() }, wantAllow: true},
+	}
+	for _, s := range steps {
+		if s.do != nil {
+			s.do()
+		}
+		if got := cb.Allow(); got != s.wantAllow {
+			t.Errorf("%s: Allow() = %t, want %t", s.name, got, s.wantAllow)
+		}
+	}
+}
+
+// statusTransport responds to every request with status
+type statusTransport int
+
+func (st statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		Status:     http.StatusText(int(st)),
+		StatusCode: int(st),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"message": "unavailable"}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestClient_ResolveLicensesCircuitBreaker(t *testing.T) {
+	c, err := NewClient("", WithHTTPClient(&http.Client{Transport: statusTransport(http.StatusServiceUnavailable)}), WithConcurrency(1), WithColor(false), WithCircuitBreaker(2, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	repos := []*Repository{
+		{Name: "github.com/a/a", Host: "github.com", Author: "a", Project: "a"},
+		{Name: "github.com/b/b", Host: "github.com", Author: "b", Project: "b"},
+		{Name: "github.com/c/c", Host: "github.com", Author: "c", Project: "c"},
+		{Name: "github.com/d/d", Host: "github.com", Author: "d", Project: "d"},
+	}
+	c.dependencies = c.resolveLicenses(context.Background(), repos, nil, false)
+
+	want := map[string]string{"github.com/a/a": "", "github.com/b/b": "", "github.com/c/c": unreachableLicense, "github.com/d/d": unreachableLicense}
+	for _, r := range c.dependencies {
+		if r.License != want[r.Name] {
+			t.Errorf("expected license %q of %s, got %q", want[r.Name], r.Name, r.License)
+		}
+	}
+	if s := c.Summary(); s[unknownLicense] != 2 || s[unreachableLicense] != 2 {
+		t.Errorf("expected 2 unknown and 2 unreachable licenses in summary, got %v", s)
+	}
+	var skipped int
+	for _, err := range c.Errors() {
+		if errors.Is(err, ErrHostUnreachable) {
+			skipped++
+		}
+	}
+	if len(c.Errors()) != 4 || skipped != 2 {
+		t.Errorf("expected 2 fetching errors and 2 unreachable host errors, got %v", c.Errors())
+	}
+
+	// dependencies without supported API, including vanity import paths resolved to other hosts,
+	// share pkg.go.dev breaker
+	got := c.resolveLicenses(context.Background(), []*Repository{
+		{Name: "example.com/a"},
+		{Name: "example.org/b", Host: "pkg.go.dev"},
+		{Name: "go.example.net/c", Host: "git.example.net", Author: "x", Project: "c"},
+	}, nil, false)
+	if errs := c.Errors(); len(errs) != 3 || !errors.Is(errs[2], ErrHostUnreachable) {
+		t.Errorf("expected 2 fetching errors and unreachable host error, got %v", errs)
+	}
+	if l := got[len(got)-1].License; l != unreachableLicense {
+		t.Errorf("expected license %q of dependency skipped by pkg.go.dev breaker, got %q", unreachableLicense, l)
+	}
+
+	if _, err := NewClient("", WithCircuitBreaker(-1, time.Minute)); err == nil {
+		t.Error("expected error for negative threshold")
+	}
+}
+
+func TestClient_ResolveLicensesCircuitBreakerNotFound(t *testing.T) {
+	c, err := NewClient("", WithHTTPClient(NewMockHTTPClient(nil)), WithConcurrency(1), WithColor(false), WithCircuitBreaker(1, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.resolveLicenses(context.Background(), []*Repository{
+		{Name: "github.com/a/a", Host: "github.com", Author: "a", Project: "a"},
+		{Name: "github.com/b/b", Host: "github.com", Author: "b", Project: "b"},
+	}, nil, false)
+	for _, err := range c.Errors() {
+		if errors.Is(err, ErrHostUnreachable) {
+			t.Errorf("expected 404 responses not to open the breaker, got %v", c.Errors())
+		}
+	}
+}
+
+func TestClient_CheckUnknownCircuitBreaker(t *testing.T) {
+	c, err := NewClient("", WithHTTPClient(&http.Client{Transport: statusTransport(http.StatusBadGateway)}), WithConcurrency(1), WithColor(false), WithCircuitBreaker(1, time.Hour), WithFailOnUnknown())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.dependencies = c.resolveLicenses(context.Background(), []*Repository{
+		{Name: "github.com/a/a", Host: "github.com", Author: "a", Project: "a"},
+		{Name: "github.com/b/b", Host: "github.com", Author: "b", Project: "b"},
+	}, nil, false)
+	if errs := c.Errors(); len(errs) != 2 || !errors.Is(errs[1], ErrHostUnreachable) {
+		t.Fatalf("expected fetching error and unreachable host error, got %v", errs)
+	}
+
+	var unknown ErrUnknownLicense
+	if c.dependencies[1].License != unreachableLicense {
+		t.Errorf("expected license %q of skipped dependency, got %q", unreachableLicense, c.dependencies[1].License)
+	}
+	if err := c.checkUnknown(); !errors.As(err, &unknown) || len(unknown.Repos) != 2 {
+		t.Errorf("checkUnknown() = %v, want both dependencies reported", err)
+	}
+}
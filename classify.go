@@ -0,0 +1,236 @@
+package glice
+
+import (
+	"embed"
+	"path"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// licenseCorpus holds texts of known licenses, named by their SPDX identifiers
+//
+//go:embed corpus/*.txt
+var licenseCorpus embed.FS
+
+// minClassifyConfidence is confidence of ClassifyLicenseText needed to use its result instead of "other"
+const minClassifyConfidence = 0.8
+
+// shingleSize is number of consecutive words compared by ClassifyLicenseText
+const shingleSize = 3
+
+// titleLines is number of first non-blank lines of license text its title is looked for in
+const titleLines = 3
+
+type licenseRule struct {
+	key string
+	// title phrases are matched in title lines only, as texts of other licenses mention the license
+	// by name, e.g. in their compatibility clauses
+	title []string
+	// phrases are matched anywhere in text of licenses that don't start with their title
+	phrases []string
+	// family groups licenses whose texts match each other's phrases, so that only the first matched
+	// license of a family is reported by detectLicenseKeys
+	family string
+}
+
+// licenseRules are matched in order, so licenses whose phrases include
+// phrases of another license (e.g. BSD-3-Clause and BSD-2-Clause) have to come first.
+var licenseRules = []licenseRule{
+	{key: "agpl-3.0", title: []string{"gnu affero general public license", "version 3"}, family: "gnu"},
+	{key: "lgpl-3.0", title: []string{"gnu lesser general public license", "version 3"}, family: "gnu"},
+	{key: "lgpl-2.1", title: []string{"gnu lesser general public license", "version 2.1"}, family: "gnu"},
+	{key: "gpl-3.0", title: []string{"gnu general public license", "version 3"}, family: "gnu"},
+	{key: "gpl-2.0", title: []string{"gnu general public license", "version 2"}, family: "gnu"},
+	{key: "mpl-2.0", title: []string{"mozilla public license", "2.0"}},
+	{key: "apache-2.0", title: []string{"apache license", "version 2.0"}},
+	{key: "epl-2.0", title: []string{"eclipse public license", "2.0"}},
+	{key: "bsl-1.0", title: []string{"boost software license", "version 1.0"}},
+	{key: "artistic-2.0", title: []string{"artistic license", "2.0"}},
+	{key: "cc0-1.0", title: []string{"creative commons", "cc0 1.0 universal"}},
+	{key: "unlicense", phrases: []string{"this is free and unencumbered software released into the public domain"}},
+	{key: "mit", phrases: []string{"permission is hereby granted, free of charge, to any person obtaining a copy"}},
+	{key: "isc", phrases: []string{"distribute this software for any purpose with or without fee is hereby granted"}},
+	{key: "zlib", phrases: []string{"this software is provided 'as-is', without any express or implied warranty", "altered source versions must be plainly marked as such"}},
+	{key: "bsd-3-clause", phrases: []string{"redistribution and use in source and binary forms", "neither the name"}, family: "bsd"},
+	{key: "bsd-2-clause", phrases: []string{"redistribution and use in source and binary forms"}, family: "bsd"},
+}
+
+// licenseText holds lowercased non-blank lines of license text, with whitespace collapsed
+type licenseText struct {
+	lines []string
+	text  string
+}
+
+func newLicenseText(text string) licenseText {
+	var lt licenseText
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.ToLower(strings.Join(strings.Fields(line), " ")); line != "" {
+			lt.lines = append(lt.lines, line)
+		}
+	}
+	lt.text = strings.Join(lt.lines, " ")
+	return lt
+}
+
+// header returns first titleLines lines of license text
+func (lt licenseText) header() string {
+	return strings.Join(lt.lines[:min(titleLines, len(lt.lines))], " ")
+}
+
+func (lt licenseText) matches(rule licenseRule) bool {
+	if rule.title != nil {
+		return containsAll(lt.header(), rule.title)
+	}
+	return containsAll(lt.text, rule.phrases)
+}
+
+// hasTitle reports whether title phrases are found in titleLines lines starting with the first of them,
+// e.g. in title of second license of dual licensed project
+func (lt licenseText) hasTitle(title []string) bool {
+	for i, line := range lt.lines {
+		if !strings.HasPrefix(strings.TrimPrefix(line, "the "), title[0]) {
+			continue
+		}
+		if containsAll(strings.Join(lt.lines[i:min(i+titleLines, len(lt.lines))], " "), title) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectLicenseKey returns license key (as used by licenseCol) of license text, or empty string if it is not recognized
+func detectLicenseKey(text string) string {
+	lt := newLicenseText(text)
+	for _, rule := range licenseRules {
+		if lt.matches(rule) {
+			return rule.key
+		}
+	}
+	return ""
+}
+
+// detectLicenseKeys returns keys of all licenses whose text is part of text, e.g. both mit and apache-2.0
+// for dual licensed projects. Licenses with title are reported only when one of text's lines starts with
+// it, so that licenses mentioned in compatibility clauses aren't. Licenses of the same family are
+// reported only once.
+func detectLicenseKeys(text string) []string {
+	lt := newLicenseText(text)
+	var keys []string
+	families := map[string]bool{}
+	for _, rule := range licenseRules {
+		if rule.family != "" && families[rule.family] || !lt.matches(rule) && (rule.title == nil || !lt.hasTitle(rule.title)) {
+			continue
+		}
+		keys = append(keys, rule.key)
+		if rule.family != "" {
+			families[rule.family] = true
+		}
+	}
+	return keys
+}
+
+// choicePhrases state that licensee may choose one of licenses in license text
+var choicePhrases = []string{"at your option", "at your choice", "dual licensed", "dual-licensed", "dually licensed", "licensed under either", "under the terms of either"}
+
+// statesLicenseChoice reports whether text lets licensee choose between its licenses, rather than
+// e.g. bundling notices of third-party code under other licenses
+func statesLicenseChoice(text string) bool {
+	lt := newLicenseText(text)
+	for _, p := range choicePhrases {
+		if strings.Contains(lt.text, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAll(s string, substrs []string) bool {
+	for _, sub := range substrs {
+		if !strings.Contains(s, sub) {
+			return false
+		}
+	}
+	return true
+}
+
+// classifyKey returns license key of license text, classifying it with ClassifyLicenseText, or with
+// phrases of known licenses when it isn't confident enough. "other" is returned for unrecognized licenses.
+func classifyKey(text string) string {
+	if id, confidence := ClassifyLicenseText(text); confidence >= minClassifyConfidence {
+		return strings.ToLower(id)
+	}
+	if key := detectLicenseKey(text); key != "" {
+		return key
+	}
+	return "other"
+}
+
+var (
+	corpusOnce     sync.Once
+	corpusShingles map[string]map[string]bool
+)
+
+// ClassifyLicenseText returns SPDX identifier of the known license most similar to text, and confidence
+// from 0 to 1 that text is that license. Similarity is Jaccard index of word trigrams, so that
+// copyright lines and reformatted text don't prevent the match. Empty identifier is returned for text
+// without words.
+func ClassifyLicenseText(text string) (spdxID string, confidence float64) {
+	corpusOnce.Do(loadLicenseCorpus)
+	sh := shingles(text)
+	if len(sh) < 1 {
+		return "", 0
+	}
+	for id, known := range corpusShingles {
+		sim := jaccard(sh, known)
+		if sim > confidence || sim == confidence && id < spdxID {
+			spdxID, confidence = id, sim
+		}
+	}
+	return spdxID, confidence
+}
+
+func loadLicenseCorpus() {
+	files, err := licenseCorpus.ReadDir("corpus")
+	if err != nil {
+		panic(err)
+	}
+	corpusShingles = make(map[string]map[string]bool, len(files))
+	for _, f := range files {
+		bts, err := licenseCorpus.ReadFile(path.Join("corpus", f.Name()))
+		if err != nil {
+			panic(err)
+		}
+		corpusShingles[strings.TrimSuffix(f.Name(), ".txt")] = shingles(string(bts))
+	}
+}
+
+// shingles returns set of shingleSize consecutive lowercased words of text, or of all its words
+// if there are fewer of them
+func shingles(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	set := map[string]bool{}
+	if len(words) > 0 && len(words) < shingleSize {
+		set[strings.Join(words, " ")] = true
+	}
+	for i := 0; i+shingleSize <= len(words); i++ {
+		set[strings.Join(words[i:i+shingleSize], " ")] = true
+	}
+	return set
+}
+
+// jaccard returns size of intersection of a and b divided by size of their union
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	var common int
+	for s := range a {
+		if b[s] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
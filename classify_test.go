@@ -0,0 +1,188 @@
+package glice
+
+import (
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetectLicenseKey(t *testing.T) {
+	tests := map[string]struct {
+		text string
+		want string
+	}{
+		"mit": {
+			text: "Permission is hereby granted, free of charge, to any person\n  obtaining a copy of this software",
+			want: "mit",
+		},
+		"apache": {
+			text: "Apache License\nVersion 2.0, January 2004",
+			want: "apache-2.0",
+		},
+		"lgpl is not gpl": {
+			text: "GNU LESSER GENERAL PUBLIC LICENSE Version 3, 29 June 2007. This version of the GNU Lesser General Public License incorporates the terms of version 3 of the GNU General Public License",
+			want: "lgpl-3.0",
+		},
+		"gpl-2.0": {
+			text: "GNU GENERAL PUBLIC LICENSE Version 2, June 1991",
+			want: "gpl-2.0",
+		},
+		"bsd-3-clause": {
+			text: "Redistribution and use in source and binary forms, with or without modification, are permitted. Neither the name of the copyright holder",
+			want: "bsd-3-clause",
+		},
+		"bsd-2-clause": {
+			text: "Redistribution and use in source and binary forms, with or without modification, are permitted",
+			want: "bsd-2-clause",
+		},
+		"mentioned outside title": {
+			text: "Example License\nCopyright 2024 Example Corp.\nAll rights reserved.\n\nThis software may be combined with software under the GNU General Public License, version 3.",
+		},
+		"unknown": {
+			text: "All rights reserved.",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := detectLicenseKey(tt.text); got != tt.want {
+				t.Errorf("detectLicenseKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCorpusLicenseKey(t *testing.T) {
+	files, err := licenseCorpus.ReadDir("corpus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		id := strings.TrimSuffix(f.Name(), ".txt")
+		t.Run(id, func(t *testing.T) {
+			bts, err := licenseCorpus.ReadFile(path.Join("corpus", f.Name()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := detectLicenseKey(string(bts)), strings.ToLower(id); got != want {
+				t.Errorf("detectLicenseKey() = %v, want %v", got, want)
+			}
+			if got, want := classifyKey(string(bts)), strings.ToLower(id); got != want {
+				t.Errorf("classifyKey() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestDetectLicenseKeys(t *testing.T) {
+	tests := map[string]struct {
+		text string
+		want []string
+	}{
+		"dual": {
+			text: "Apache License\nVersion 2.0, January 2004\n\nPermission is hereby granted, free of charge, to any person obtaining a copy",
+			want: []string{"apache-2.0", "mit"},
+		},
+		"single family member": {
+			text: "GNU LESSER GENERAL PUBLIC LICENSE Version 3. This version incorporates the terms of version 3 of the GNU General Public License",
+			want: []string{"lgpl-3.0"},
+		},
+		"second license title": {
+			text: "Permission is hereby granted, free of charge, to any person obtaining a copy\nof this software.\n\n---\n\n                                 Apache License\n                           Version 2.0, January 2004",
+			want: []string{"apache-2.0", "mit"},
+		},
+		"bsd-3-clause only": {
+			text: "Redistribution and use in source and binary forms, with or without modification, are permitted. Neither the name of the copyright holder",
+			want: []string{"bsd-3-clause"},
+		},
+		"unknown": {
+			text: "All rights reserved.",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := detectLicenseKeys(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("detectLicenseKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectLicenseKeysCompatibilityClause(t *testing.T) {
+	// MPL-2.0 and EPL-2.0 name GPL licenses as their secondary licenses
+	for _, id := range []string{"MPL-2.0", "EPL-2.0"} {
+		t.Run(id, func(t *testing.T) {
+			bts, err := licenseCorpus.ReadFile(path.Join("corpus", id+".txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := detectLicenseKeys(string(bts)), []string{strings.ToLower(id)}; !reflect.DeepEqual(got, want) {
+				t.Errorf("detectLicenseKeys() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+const iscText = `ISC License
+
+Copyright (c) 2012-2016 Dave Collins <dave@davec.name>
+
+Permission to use, copy, modify, and/or distribute this software for any
+purpose with or without fee is hereby granted, provided that the above
+copyright notice and this permission notice appear in all copies.
+
+THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+`
+
+func TestClassifyLicenseText(t *testing.T) {
+	mit, err := licenseCorpus.ReadFile("corpus/MIT.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		text     string
+		wantID   string
+		wantHigh bool
+		wantKey  string
+	}{
+		"isc": {text: iscText, wantID: "ISC", wantHigh: true, wantKey: "isc"},
+		"mit with copyright": {
+			text:   "MIT License\n\nCopyright (c) 2024 Emir Ribic\n\n" + string(mit),
+			wantID: "MIT", wantHigh: true, wantKey: "mit",
+		},
+		"reformatted zlib": {
+			text: `This software is provided 'as-is', without any express or implied warranty. In no event will the
+authors be held liable for any damages arising from the use of this software. Permission is granted to anyone
+to use this software for any purpose, including commercial applications, and to alter it and redistribute it
+freely, subject to the following restrictions: 1. The origin of this software must not be misrepresented; you
+must not claim that you wrote the original software. If you use this software in a product, an acknowledgment
+in the product documentation would be appreciated but is not required. 2. Altered source versions must be
+plainly marked as such, and must not be misrepresented as being the original software. 3. This notice may not
+be removed or altered from any source distribution.`,
+			wantID: "Zlib", wantHigh: true, wantKey: "zlib",
+		},
+		"proprietary": {text: "Copyright 2024 Example Corp. All rights reserved. Do not distribute.", wantKey: "other"},
+		"empty":       {text: " \n", wantKey: "other"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			id, confidence := ClassifyLicenseText(tt.text)
+			if tt.wantID != "" && id != tt.wantID {
+				t.Errorf("ClassifyLicenseText() = %s, want %s", id, tt.wantID)
+			}
+			if high := confidence >= minClassifyConfidence; high != tt.wantHigh {
+				t.Errorf("ClassifyLicenseText() confidence = %.2f, want high %t", confidence, tt.wantHigh)
+			}
+			if got := classifyKey(tt.text); got != tt.wantKey {
+				t.Errorf("classifyKey() = %s, want %s", got, tt.wantKey)
+			}
+		})
+	}
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/ribice/glice/v2"
 )
@@ -13,17 +16,50 @@ import (
 func main() {
 	var (
 		fileWrite = flag.Bool("f", false, "Write all licenses to files")
+		checksums = flag.Bool("checksums", false, "Write SHA-256 checksums of license files written with -f to CHECKSUMS.txt")
+		notice    = flag.String("notice", "", "Directory to write AGGREGATE-NOTICES.txt with license texts of all dependencies to")
 		indirect  = flag.Bool("i", false, "Gets indirect modules as well")
+		work      = flag.Bool("w", false, "Gets dependencies of all modules from go.work if present")
+		recursive = flag.Bool("r", false, "Gets dependencies of all modules in directory tree of the scanned path, e.g. of a monorepo")
 		path      = flag.String("p", "", `Path of desired directory to be scanned with Glice (e.g. "github.com/ribice/glice/v2")`)
 		thx       = flag.Bool("t", false, "Stars dependent repos. Needs GITHUB_API_KEY env variable to work")
 		verbose   = flag.Bool("v", false, "Adds verbose logging")
-		format    = flag.String("fmt", "table", "Output format [table | json | csv]")
-		output    = flag.String("o", "stdout", "Output location [stdout | file]")
-		extension = map[string]string{
-			"table": "txt",
-			"json":  "json",
-			"csv":   "csv",
-		}
+		authFlow  = flag.Bool("interactive-auth", false, "Authenticate with GitHub in browser when GITHUB_API_KEY isn't set. Needs GLICE_GITHUB_CLIENT_ID env variable to work")
+		tagged    = flag.Bool("tagged-licenses", false, "Read licenses of GitHub dependencies at tag of their version instead of default branch")
+		graphQL   = flag.Bool("graphql", false, "Fetch GitHub licenses in batches with GraphQL API. Needs GITHUB_API_KEY env variable to work")
+		noProg    = flag.Bool("no-progress", false, "Hides progress bar shown on stderr while licenses are fetched")
+		format    = flag.String("fmt", "table", "Output format [table | json | csv | spdx-json | spdx-tv | html | markdown | yaml | template | xml | junit | cyclonedx-json | cyclonedx-xml | sarif | ndjson | dot | compat | fossa | benchmark]")
+		output    = flag.String("o", "stdout", "Comma separated output locations [stdout | file | both]")
+		tmpl      = flag.String("tmpl", "", "Path to text/template file used with template format")
+		timeout   = flag.Duration("timeout", 10*time.Second, "Timeout of a single license request")
+		retries   = flag.Int("retries", 1, "Number of attempts for license requests failing with transient errors")
+		breaker   = flag.Int("circuit-breaker", 0, "Number of consecutive failed license requests after which host is skipped for a minute, 0 disables it")
+		conc      = flag.Int("concurrency", 5, "Number of licenses fetched at the same time")
+		cacheDir  = flag.String("cache", "", "Directory where fetched licenses are cached between runs")
+		cacheTTL  = flag.Duration("cache-ttl", 24*time.Hour, "How long cached licenses are valid, 0 means forever")
+		vendor    = flag.Bool("vendor", false, "Read licenses of vendored modules from vendor directory instead of fetching them")
+		ignore    = flag.String("ignore", "", `Comma separated list of ignored modules, supports glob patterns (e.g. "golang.org/x/*")`)
+		sortBy    = flag.String("sort", "", `Sort dependencies by field [name | license | host | version], optionally followed by direction (e.g. "license:desc")`)
+		columns   = flag.String("columns", "", "Comma separated list of table columns [dependency | url | license | version | host | author | category | stars | sha | vulns | risk]")
+		gitlab    = flag.String("gitlab-hosts", "", "Comma separated list of self-hosted GitLab instances (e.g. gitlab.example.com), GITLAB_API_KEY is used as their API token")
+		gitea     = flag.String("gitea-hosts", "", "Comma separated list of Gitea or Forgejo instances (e.g. codeberg.org), GITEA_API_KEY is used as their API token")
+		vulns     = flag.Bool("vulns", false, "Look up known vulnerabilities of dependencies in OSV.dev database, shown by vulns table column")
+		toolchain = flag.Bool("toolchain", false, "Include Go toolchain from go.mod toolchain directive as golang.org/toolchain dependency")
+		pageSize  = flag.Int("page-size", 0, "Number of table rows printed to terminal before waiting for Enter, 0 disables paging")
+		dryRun    = flag.Bool("dry-run", false, "List dependencies from go.mod without fetching their licenses or any other network calls")
+		watch     = flag.Bool("watch", false, "Print dependencies again each time go.mod changes, until interrupted")
+		noStdlib  = flag.Bool("exclude-stdlib", false, "Skip standard library and golang.org/x/ modules, which are part of Go project")
+		prefer    = flag.String("prefer-license", "", "License selected for dual licensed dependencies (e.g. MIT), the least restrictive one is selected when it isn't available")
+		summary   = flag.Bool("summary", false, "Print number of dependencies per license, supported by table and json formats")
+		legacy    = flag.Bool("legacy-json", false, "Print json format as array of dependencies instead of object with modules and summary")
+		pretty    = flag.Bool("pretty", false, "Pretty-print json format")
+		csvDelim  = flag.String("csv-delimiter", ",", `Field delimiter used by csv format, "\t" for TSV`)
+		csvBOM    = flag.Bool("csv-bom", false, "Prepend UTF-8 byte order mark to csv format, for Excel compatibility")
+		allow     = flag.String("allow", "", "Comma separated list of allowed licenses (e.g. MIT,Apache-2.0). Exits with non-zero code when violated")
+		block     = flag.String("block", "", "Comma separated list of blocked licenses (e.g. GPL-3.0,AGPL-3.0). Exits with non-zero code when violated")
+		copyleft  = flag.Bool("fail-copyleft", false, "Exit with non-zero code when dependencies with strong or network copyleft license (e.g. GPL, AGPL) are found")
+		unknown   = flag.Bool("fail-unknown", false, "Exit with non-zero code when dependencies without license or with unidentified license are found")
+		warnOnly  = flag.Bool("warn", false, "Only warn about allowed/blocked license violations instead of exiting with non-zero code")
 	)
 
 	flag.Parse()
@@ -39,29 +75,155 @@ func main() {
 		log.SetFlags(0)
 	}
 
-	cl, err := glice.NewClient(*path, *format, *output)
+	opts := []glice.Option{glice.WithFormat(*format), glice.WithOutputs(strings.Split(*output, ",")...), glice.WithConcurrency(*conc), glice.WithTimeout(*timeout), glice.WithRetry(*retries, time.Second)}
+	if *authFlow {
+		opts = append(opts, glice.WithInteractiveAuth())
+	}
+	if *breaker > 0 {
+		opts = append(opts, glice.WithCircuitBreaker(*breaker, time.Minute))
+	}
+	if *ignore != "" {
+		opts = append(opts, glice.WithIgnore(strings.Split(*ignore, ",")...))
+	}
+	if *sortBy != "" {
+		field, dir, ok := strings.Cut(*sortBy, ":")
+		if !ok {
+			dir = "asc"
+		}
+		opts = append(opts, glice.WithSort(field, dir))
+	}
+	if *graphQL {
+		opts = append(opts, glice.WithGraphQL())
+	}
+	if *tagged {
+		opts = append(opts, glice.WithTaggedLicenses())
+	}
+	if *copyleft {
+		opts = append(opts, glice.WithFailOnCopyleft())
+	}
+	if *unknown {
+		opts = append(opts, glice.WithFailOnUnknown())
+	}
+	if *gitlab != "" {
+		hosts := strings.Split(*gitlab, ",")
+		opts = append(opts, glice.WithGitLabHosts(hosts...))
+		if key := os.Getenv("GITLAB_API_KEY"); key != "" {
+			for _, h := range hosts {
+				opts = append(opts, glice.WithAPIKey(h, key))
+			}
+		}
+	}
+	if *gitea != "" {
+		opts = append(opts, glice.WithGiteaHosts(strings.Split(*gitea, ",")...))
+	}
+	if *toolchain {
+		opts = append(opts, glice.WithIncludeToolchain())
+	}
+	if *pageSize > 0 {
+		opts = append(opts, glice.WithPageSize(*pageSize))
+	}
+	if *dryRun {
+		opts = append(opts, glice.WithDryRun())
+	}
+	if *noStdlib {
+		opts = append(opts, glice.WithExcludeStdlib())
+	}
+	if *prefer != "" {
+		opts = append(opts, glice.WithPreferredSPDX(*prefer))
+	}
+	if *summary {
+		opts = append(opts, glice.WithSummary())
+	}
+	if *legacy {
+		opts = append(opts, glice.WithLegacyJSON())
+	}
+	if *pretty {
+		opts = append(opts, glice.WithJSONIndent("", "  "))
+	}
+	if *csvDelim != "," {
+		delim := []rune(strings.ReplaceAll(*csvDelim, `\t`, "\t"))
+		if len(delim) != 1 {
+			checkErr(fmt.Errorf("csv delimiter has to be a single character, got %q", *csvDelim))
+		}
+		opts = append(opts, glice.WithCSVDelimiter(delim[0]))
+	}
+	if *csvBOM {
+		opts = append(opts, glice.WithCSVBOM(true))
+	}
+	if *columns != "" {
+		opts = append(opts, glice.WithColumns(strings.Split(*columns, ",")...))
+	}
+	if *noProg {
+		opts = append(opts, glice.WithProgress(false))
+	}
+	if *vendor {
+		opts = append(opts, glice.WithVendor())
+	}
+	if *work {
+		opts = append(opts, glice.WithWorkspace())
+	}
+	if *cacheDir != "" {
+		opts = append(opts, glice.WithCache(*cacheDir, *cacheTTL))
+	}
+	if *tmpl != "" {
+		bts, err := os.ReadFile(*tmpl)
+		checkErr(err)
+		opts = append(opts, glice.WithTemplate(string(bts)))
+	}
+
+	if *watch {
+		checkErr(glice.Watch(*path, *indirect, os.Stdout, opts...))
+		return
+	}
+
+	newClient := glice.NewClient
+	if *recursive {
+		newClient = glice.NewClientForRoot
+	}
+	cl, err := newClient(*path, opts...)
 	checkErr(err)
+	if *allow != "" {
+		cl.AllowedLicenses = strings.Split(*allow, ",")
+	}
+	if *block != "" {
+		cl.BlockedLicenses = strings.Split(*block, ",")
+	}
 
 	checkErr(cl.ParseDependencies(*indirect, *thx))
-
-	switch *output {
-	case "stdout":
-		cl.Print(os.Stdout)
-	case "file":
-		fileName := fmt.Sprintf("dependencies.%s", extension[*format])
-		f, err := os.Create(fileName)
+	if *vulns && !*dryRun {
+		_, err := cl.CheckVulnerabilities(context.Background())
 		checkErr(err)
-		cl.Print(f)
-		f.Close()
 	}
 
-	if *fileWrite {
+	checkErr(cl.Write())
+	if *dryRun {
+		// licenses weren't fetched, so there's nothing to write or check
+		return
+	}
+
+	if *fileWrite && *checksums {
+		checkErr(cl.WriteLicensesWithHashes())
+	} else if *fileWrite {
 		checkErr(cl.WriteLicensesToFile())
 	}
+
+	if *notice != "" {
+		checkErr(cl.WriteNoticeFile(*notice))
+	}
+
+	if err := cl.Check(glice.CheckMode{}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if !*warnOnly {
+			os.Exit(1)
+		}
+	}
 }
 
+// checkErr prints err to stderr and exits with non-zero code. It doesn't use log, which is
+// discarded without -v.
 func checkErr(err error) {
 	if err != nil {
-		log.Fatal(err)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 }
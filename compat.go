@@ -0,0 +1,148 @@
+package glice
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// compatibilityRules tell whether two licenses (lowercase SPDX identifiers) can be distributed together in
+// the same binary. Pairs without a rule are compatible when at least one license is permissive or public domain.
+var compatibilityRules = map[string]map[string]bool{
+	"apache-2.0": {"gpl-2.0": false, "gpl-3.0": true, "agpl-3.0": true, "lgpl-3.0": true, "mpl-2.0": true},
+	"gpl-2.0":    {"gpl-3.0": false, "agpl-3.0": false, "lgpl-3.0": false, "mpl-2.0": true, "epl-2.0": false},
+	"gpl-3.0":    {"agpl-3.0": true, "lgpl-2.1": true, "lgpl-3.0": true, "mpl-2.0": true, "epl-2.0": false},
+	"agpl-3.0":   {"lgpl-3.0": true, "mpl-2.0": true, "epl-2.0": false},
+	"lgpl-2.1":   {"lgpl-3.0": true, "mpl-2.0": true},
+	"lgpl-3.0":   {"mpl-2.0": true},
+	"mpl-2.0":    {"epl-2.0": false},
+}
+
+// CompatibilityMatrix returns for each pair of licenses used by dependencies whether they are compatible
+// for distribution together in the same binary. Built-in rules can be extended with WithCompatibilityRules.
+// Dependencies without license or with unidentified one are left out.
+func (c *Client) CompatibilityMatrix() map[string]map[string]bool {
+	licenses := c.compatLicenses()
+	matrix := make(map[string]map[string]bool, len(licenses))
+	for _, a := range licenses {
+		matrix[a] = make(map[string]bool, len(licenses))
+		for _, b := range licenses {
+			matrix[a][b] = c.compatible(a, b)
+		}
+	}
+	return matrix
+}
+
+// compatLicenses returns sorted licenses of dependencies, leaving out unknown ones
+func (c *Client) compatLicenses() []string {
+	seen := map[string]bool{}
+	var licenses []string
+	for _, d := range c.dependencies {
+		if isUnknownLicense(d) || seen[d.License] {
+			continue
+		}
+		seen[d.License] = true
+		licenses = append(licenses, d.License)
+	}
+	sort.Strings(licenses)
+	return licenses
+}
+
+// laterVersions are later versions of licenses, which -or-later forms of the license can be used under
+var laterVersions = map[string][]string{
+	"gpl-2.0":  {"gpl-3.0"},
+	"lgpl-2.1": {"lgpl-3.0"},
+}
+
+// compatKey returns lowercase SPDX identifier license is looked up by in compatibility rules. -only
+// form is the same as base license, while -or-later (or +) form is kept, as it allows later versions.
+func compatKey(license string) string {
+	id := strings.ToLower(NormalizeSPDX(license))
+	if base, ok := strings.CutSuffix(id, "+"); ok {
+		id = base + "-or-later"
+	}
+	return strings.TrimSuffix(id, "-only")
+}
+
+// licenseVersions returns licenses key can be used under: base license and its later versions for
+// -or-later forms, key itself otherwise
+func licenseVersions(key string) []string {
+	base, ok := strings.CutSuffix(key, "-or-later")
+	if !ok {
+		return []string{key}
+	}
+	return append([]string{base}, laterVersions[base]...)
+}
+
+// compatible reports whether licenses a and b can be distributed together. Rules set with
+// WithCompatibilityRules take precedence over built-in ones, and -or-later forms without own rule are
+// compatible when any version they can be used under is.
+func (c *Client) compatible(a, b string) bool {
+	a, b = compatKey(a), compatKey(b)
+	if a == b {
+		return true
+	}
+	if ok, found := c.compatRule(a, b); found {
+		return ok
+	}
+	for _, x := range licenseVersions(a) {
+		for _, y := range licenseVersions(b) {
+			if c.compatibleVersions(x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compatibleVersions looks up rules for the pair of licenses, which are compatible without rule when
+// at least one of them is permissive or public domain
+func (c *Client) compatibleVersions(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if ok, found := c.compatRule(a, b); found {
+		return ok
+	}
+	permissive := func(l string) bool {
+		cat := LicenseCategory(l)
+		return cat == CategoryPermissive || cat == CategoryPublicDomain
+	}
+	return permissive(a) || permissive(b)
+}
+
+// compatRule looks up rule for the pair of licenses in both orders
+func (c *Client) compatRule(a, b string) (ok, found bool) {
+	for _, rules := range []map[string]map[string]bool{c.compatRules, compatibilityRules} {
+		if ok, found := rules[a][b]; found {
+			return ok, true
+		}
+		if ok, found := rules[b][a]; found {
+			return ok, true
+		}
+	}
+	return false, false
+}
+
+// printCompat prints compatibility matrix as a table, with a row and column per license
+func (c *Client) printCompat(writeTo io.Writer) {
+	licenses := c.compatLicenses()
+	matrix := c.CompatibilityMatrix()
+
+	tw := tablewriter.NewWriter(writeTo)
+	tw.SetHeader(append([]string{"License"}, licenses...))
+	for _, a := range licenses {
+		row := []string{a}
+		for _, b := range licenses {
+			cell := "no"
+			if matrix[a][b] {
+				cell = "yes"
+			}
+			row = append(row, cell)
+		}
+		tw.Append(row)
+	}
+	tw.Render()
+}
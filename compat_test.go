@@ -0,0 +1,81 @@
+package glice
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClient_CompatibilityMatrix(t *testing.T) {
+	deps := []*Repository{
+		{Name: "github.com/fatih/color", License: "MIT"},
+		{Name: "github.com/some/apache", License: "Apache-2.0"},
+		{Name: "github.com/some/gpl2", License: "GPL-2.0"},
+		{Name: "github.com/some/gpl3", License: "GPL-3.0"},
+		{Name: "github.com/some/gpl2only", License: "GPL-2.0-only"},
+		{Name: "github.com/some/gpl2later", License: "GPL-2.0-or-later"},
+		{Name: "github.com/some/gpl3later", License: "GPL-3.0-or-later"},
+		{Name: "github.com/some/gpl3only", License: "GPL-3.0-only"},
+		{Name: "github.com/some/lgpl", License: "LGPL-2.1-or-later"},
+		{Name: "github.com/some/other", License: "Other"},
+		{Name: "github.com/some/none"},
+	}
+	c := &Client{dependencies: deps}
+	got := c.CompatibilityMatrix()
+	if !reflect.DeepEqual(keys(got), []string{"Apache-2.0", "GPL-2.0", "GPL-2.0-only", "GPL-2.0-or-later", "GPL-3.0", "GPL-3.0-only", "GPL-3.0-or-later", "LGPL-2.1-or-later", "MIT"}) {
+		t.Fatalf("expected matrix of known licenses, got %v", keys(got))
+	}
+
+	tests := map[string]struct {
+		a, b string
+		want bool
+	}{
+		"same license":          {a: "GPL-2.0", b: "GPL-2.0", want: true},
+		"mit and apache":        {a: "MIT", b: "Apache-2.0", want: true},
+		"gpl3 and apache":       {a: "GPL-3.0", b: "Apache-2.0", want: true},
+		"gpl2 and apache":       {a: "GPL-2.0", b: "Apache-2.0", want: false},
+		"apache and gpl2":       {a: "Apache-2.0", b: "GPL-2.0", want: false},
+		"gpl2 and gpl3":         {a: "GPL-2.0", b: "GPL-3.0", want: false},
+		"permissive and gpl":    {a: "GPL-3.0", b: "MIT", want: true},
+		"gpl2 only and apache":  {a: "GPL-2.0-only", b: "Apache-2.0", want: false},
+		"apache and gpl3 later": {a: "Apache-2.0", b: "GPL-3.0-or-later", want: true},
+		"gpl2 only and gpl3":    {a: "GPL-2.0-only", b: "GPL-3.0", want: false},
+		"gpl3 only and lgpl":    {a: "GPL-3.0-only", b: "LGPL-2.1-or-later", want: true},
+		"gpl2 later and apache": {a: "GPL-2.0-or-later", b: "Apache-2.0", want: true},
+		"apache and gpl2 later": {a: "Apache-2.0", b: "GPL-2.0-or-later", want: true},
+		"gpl2 later and gpl3":   {a: "GPL-2.0-or-later", b: "GPL-3.0", want: true},
+		"gpl2 later and gpl2":   {a: "GPL-2.0-or-later", b: "GPL-2.0-only", want: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got[tt.a][tt.b] != tt.want {
+				t.Errorf("CompatibilityMatrix()[%s][%s] = %t, want %t", tt.a, tt.b, got[tt.a][tt.b], tt.want)
+			}
+		})
+	}
+
+	WithCompatibilityRules(map[string]map[string]bool{"mit": {"GPL-3.0": false}})(c)
+	WithCompatibilityRules(map[string]map[string]bool{"GPL-2.0": {"gpl-3.0": true}})(c)
+	got = c.CompatibilityMatrix()
+	if got["GPL-3.0"]["MIT"] || !got["GPL-2.0"]["GPL-3.0"] || !got["MIT"]["Apache-2.0"] {
+		t.Errorf("expected custom rules to override built-in ones, got %v", got)
+	}
+}
+
+func TestClient_PrintCompat(t *testing.T) {
+	c := &Client{format: "compat", dependencies: []*Repository{
+		{Name: "github.com/some/apache", License: "Apache-2.0"},
+		{Name: "github.com/some/gpl2", License: "GPL-2.0"},
+	}}
+	output := &bytes.Buffer{}
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+	got := output.String()
+	for _, want := range []string{"| APACHE-2.0 | GPL-2.0 |", "| Apache-2.0 | yes        | no      |", "| GPL-2.0    | no         | yes     |"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, got)
+		}
+	}
+}
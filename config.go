@@ -0,0 +1,210 @@
+package glice
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFiles are loaded from the working directory by NewClientFromConfig when no config path is given
+var defaultConfigFiles = []string{"glice.yaml", ".glice.yaml"}
+
+// Config configures Client, usually loaded from YAML file with NewClientFromConfig.
+// Zero values keep defaults of NewClient.
+type Config struct {
+	// Path of the directory containing go.mod, relative paths are resolved against config file directory
+	Path   string `yaml:"path"`
+	Format string `yaml:"format"`
+	// Output is comma separated list of outputs, e.g. "stdout,file"
+	Output string `yaml:"output"`
+	// Indirect makes ParseDependencies include indirect dependencies, see WithIndirect
+	Indirect        bool              `yaml:"indirect"`
+	Concurrency     int               `yaml:"concurrency"`
+	Timeout         time.Duration     `yaml:"timeout"`
+	AllowedLicenses []string          `yaml:"allowed_licenses"`
+	BlockedLicenses []string          `yaml:"blocked_licenses"`
+	Ignore          []string          `yaml:"ignore"`
+	APIKeys         map[string]string `yaml:"api_keys"`
+	// LicenseOverrides maps import paths to licenses used instead of the fetched ones
+	LicenseOverrides map[string]string `yaml:"license_overrides"`
+	Cache            *CacheConfig      `yaml:"cache"`
+}
+
+// CacheConfig configures on-disk license cache, see WithCache
+type CacheConfig struct {
+	Dir string        `yaml:"dir"`
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// LoadConfig reads YAML config file at path. Unknown fields are reported as errors.
+func LoadConfig(path string) (*Config, error) {
+	bts, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	dec := yaml.NewDecoder(bytes.NewReader(bts))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	if cfg.Path == "" {
+		cfg.Path = filepath.Dir(path)
+	} else if !filepath.IsAbs(cfg.Path) {
+		cfg.Path = filepath.Join(filepath.Dir(path), cfg.Path)
+	}
+	return cfg, nil
+}
+
+// NewClientFromConfig creates a client from YAML config file at configPath. If configPath is empty,
+// glice.yaml or .glice.yaml from the working directory is used, or defaults if neither exists.
+func NewClientFromConfig(configPath string) (*Client, error) {
+	if configPath == "" {
+		configPath = findConfig()
+	}
+	if configPath == "" {
+		return (&Config{}).NewClient()
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.NewClient()
+}
+
+func findConfig() string {
+	for _, f := range defaultConfigFiles {
+		if _, err := os.Stat(f); err == nil {
+			return f
+		}
+	}
+	return ""
+}
+
+// NewClient validates config and creates a client from it
+func (cfg *Config) NewClient() (*Client, error) {
+	if cfg.Concurrency < 0 {
+		return nil, fmt.Errorf("invalid concurrency provided (%d) - has to be positive", cfg.Concurrency)
+	}
+	if cfg.Timeout < 0 {
+		return nil, fmt.Errorf("invalid timeout provided (%s) - has to be positive", cfg.Timeout)
+	}
+	if cfg.Cache != nil && cfg.Cache.Dir == "" {
+		return nil, errors.New("cache dir has to be set when cache is configured")
+	}
+
+	path := cfg.Path
+	if path == "" {
+		path = "."
+	}
+	c, err := NewClient(path, cfg.Options()...)
+	if err != nil {
+		return nil, err
+	}
+	c.AllowedLicenses = cfg.AllowedLicenses
+	c.BlockedLicenses = cfg.BlockedLicenses
+	return c, nil
+}
+
+// Options returns client options equivalent to config, besides allowed and blocked licenses
+func (cfg *Config) Options() []Option {
+	var opts []Option
+	if cfg.Format != "" {
+		opts = append(opts, WithFormat(cfg.Format))
+	}
+	if cfg.Output != "" {
+		opts = append(opts, WithOutputs(strings.Split(cfg.Output, ",")...))
+	}
+	if cfg.Indirect {
+		opts = append(opts, WithIndirect())
+	}
+	if cfg.Concurrency > 0 {
+		opts = append(opts, WithConcurrency(cfg.Concurrency))
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, WithTimeout(cfg.Timeout))
+	}
+	if len(cfg.Ignore) > 0 {
+		opts = append(opts, WithIgnore(cfg.Ignore...))
+	}
+	for host, key := range cfg.APIKeys {
+		opts = append(opts, WithAPIKey(host, key))
+	}
+	if cfg.Cache != nil {
+		opts = append(opts, WithCache(cfg.Cache.Dir, cfg.Cache.TTL))
+	}
+	if len(cfg.LicenseOverrides) > 0 {
+		opts = append(opts, WithLicenseOverrides(cfg.LicenseOverrides))
+	}
+	return opts
+}
+
+// NewClientFromEnv creates a client from GLICE_PATH, GLICE_FORMAT, GLICE_OUTPUT, GLICE_INDIRECT,
+// GLICE_CONCURRENCY, GLICE_TIMEOUT, GLICE_ALLOWED_LICENSES, GLICE_BLOCKED_LICENSES and GLICE_IGNORE
+// environment variables, e.g. in CI pipelines. Lists are comma separated, unset variables keep defaults.
+func NewClientFromEnv() (*Client, error) {
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.NewClient()
+}
+
+// ConfigFromEnv returns config read from environment variables documented by NewClientFromEnv
+func ConfigFromEnv() (*Config, error) {
+	cfg := &Config{
+		Path:            os.Getenv("GLICE_PATH"),
+		Format:          os.Getenv("GLICE_FORMAT"),
+		Output:          os.Getenv("GLICE_OUTPUT"),
+		AllowedLicenses: envList("GLICE_ALLOWED_LICENSES"),
+		BlockedLicenses: envList("GLICE_BLOCKED_LICENSES"),
+		Ignore:          envList("GLICE_IGNORE"),
+	}
+	if v := os.Getenv("GLICE_INDIRECT"); v != "" {
+		indirect, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GLICE_INDIRECT provided (%s) - has to be true or false", v)
+		}
+		cfg.Indirect = indirect
+	}
+	if v := os.Getenv("GLICE_CONCURRENCY"); v != "" {
+		concurrency, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GLICE_CONCURRENCY provided (%s) - has to be a number", v)
+		}
+		if concurrency < 1 {
+			return nil, fmt.Errorf("invalid concurrency provided (%d) - has to be positive", concurrency)
+		}
+		cfg.Concurrency = concurrency
+	}
+	if v := os.Getenv("GLICE_TIMEOUT"); v != "" {
+		timeout, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GLICE_TIMEOUT provided (%s) - has to be a duration, e.g. 30s", v)
+		}
+		cfg.Timeout = timeout
+	}
+	return cfg, nil
+}
+
+// envList returns comma separated values of environment variable key, without empty ones
+func envList(key string) []string {
+	var list []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,168 @@
+package glice
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClientFromConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "glice.yaml")
+	cfg := `path: ` + wd() + `
+format: json
+output: stdout,file
+concurrency: 2
+timeout: 3s
+allowed_licenses: [MIT, Apache-2.0]
+blocked_licenses:
+  - GPL-3.0
+ignore:
+  - golang.org/x/*
+api_keys:
+  github.com: key
+cache:
+  dir: ` + filepath.Join(dir, "cache") + `
+  ttl: 1h
+license_overrides:
+  example.com/mirror: MIT
+`
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewClientFromConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.format != "json" || !reflect.DeepEqual(c.outputs, []string{"stdout", "file"}) || c.concurrency != 2 || c.timeout != 3*time.Second {
+		t.Errorf("config was not applied: %+v", c)
+	}
+	if !reflect.DeepEqual(c.AllowedLicenses, []string{"MIT", "Apache-2.0"}) || !reflect.DeepEqual(c.BlockedLicenses, []string{"GPL-3.0"}) {
+		t.Errorf("unexpected licenses %v, %v", c.AllowedLicenses, c.BlockedLicenses)
+	}
+	if !reflect.DeepEqual(c.ignore, []string{"golang.org/x/*"}) || c.apiKeys["github.com"] != "key" {
+		t.Errorf("unexpected ignore or API keys: %v, %v", c.ignore, c.apiKeys)
+	}
+	if c.LicenseOverrides["example.com/mirror"] != "MIT" {
+		t.Errorf("unexpected license overrides %v", c.LicenseOverrides)
+	}
+	if c.cache == nil || c.cache.ttl != time.Hour {
+		t.Errorf("unexpected cache %+v", c.cache)
+	}
+}
+
+func TestNewClientFromConfig_Invalid(t *testing.T) {
+	base := "path: " + wd() + "\n"
+	tests := map[string]string{
+		"unknown field":     base + "formatt: json\n",
+		"invalid format":    base + "format: pdf\n",
+		"invalid output":    base + "output: stdout,printer\n",
+		"invalid timeout":   base + "timeout: soon\n",
+		"negative workers":  base + "concurrency: -1\n",
+		"cache without dir": base + "cache:\n  ttl: 1h\n",
+		"missing go.mod":    "path: missing\n",
+	}
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			p := filepath.Join(dir, "glice.yaml")
+			if err := os.WriteFile(p, []byte(cfg), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := NewClientFromConfig(p); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestNewClientFromConfig_Default(t *testing.T) {
+	dir := t.TempDir()
+	cwd := wd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(cwd)
+
+	if _, err := NewClientFromConfig(""); err != ErrNoGoMod {
+		t.Errorf("expected ErrNoGoMod without config and go.mod, got %v", err)
+	}
+
+	if err := os.WriteFile(".glice.yaml", []byte("path: "+cwd+"\nformat: csv\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewClientFromConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.format != "csv" {
+		t.Errorf("expected .glice.yaml to be loaded, got format %s", c.format)
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	env := map[string]string{
+		"GLICE_PATH":             wd(),
+		"GLICE_FORMAT":           "json",
+		"GLICE_OUTPUT":           "stdout,file",
+		"GLICE_INDIRECT":         "true",
+		"GLICE_CONCURRENCY":      "2",
+		"GLICE_TIMEOUT":          "3s",
+		"GLICE_ALLOWED_LICENSES": "MIT, Apache-2.0",
+		"GLICE_BLOCKED_LICENSES": "GPL-3.0",
+		"GLICE_IGNORE":           "golang.org/x/*,github.com/fatih/*,",
+	}
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+
+	c, err := NewClientFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.path != wd() || c.format != "json" || !reflect.DeepEqual(c.outputs, []string{"stdout", "file"}) || !c.indirect || c.concurrency != 2 || c.timeout != 3*time.Second {
+		t.Errorf("environment was not applied: %+v", c)
+	}
+	if !reflect.DeepEqual(c.AllowedLicenses, []string{"MIT", "Apache-2.0"}) || !reflect.DeepEqual(c.BlockedLicenses, []string{"GPL-3.0"}) {
+		t.Errorf("unexpected licenses %v, %v", c.AllowedLicenses, c.BlockedLicenses)
+	}
+	if !reflect.DeepEqual(c.ignore, []string{"golang.org/x/*", "github.com/fatih/*"}) {
+		t.Errorf("unexpected ignore %v", c.ignore)
+	}
+}
+
+func TestNewClientFromEnv_Invalid(t *testing.T) {
+	tests := map[string]map[string]string{
+		"invalid indirect":    {"GLICE_INDIRECT": "maybe"},
+		"invalid concurrency": {"GLICE_CONCURRENCY": "many"},
+		"negative workers":    {"GLICE_CONCURRENCY": "-1"},
+		"zero workers":        {"GLICE_CONCURRENCY": "0"},
+		"invalid timeout":     {"GLICE_TIMEOUT": "soon"},
+		"invalid format":      {"GLICE_FORMAT": "pdf"},
+		"missing go.mod":      {"GLICE_PATH": "missing"},
+	}
+	for name, env := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("GLICE_PATH", wd())
+			for k, v := range env {
+				t.Setenv(k, v)
+			}
+			if _, err := NewClientFromEnv(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestConfigFromEnv_NonPositiveConcurrency(t *testing.T) {
+	for _, v := range []string{"0", "-2"} {
+		t.Setenv("GLICE_CONCURRENCY", v)
+		if _, err := ConfigFromEnv(); err == nil || !strings.Contains(err.Error(), "has to be positive") {
+			t.Errorf("ConfigFromEnv() with GLICE_CONCURRENCY=%s = %v, want positive concurrency error", v, err)
+		}
+	}
+}
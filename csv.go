@@ -0,0 +1,47 @@
+package glice
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"unicode/utf8"
+)
+
+// utf8BOM is prepended to CSV output with WithCSVBOM, so that Excel detects UTF-8 encoding
+const utf8BOM = "\xEF\xBB\xBF"
+
+func (c *Client) printCSV(ctx context.Context, writeTo io.Writer) error {
+	if c.csvBOM {
+		if _, err := io.WriteString(writeTo, utf8BOM); err != nil {
+			return err
+		}
+	}
+
+	csvW := csv.NewWriter(writeTo)
+	if c.csvDelimiter != 0 {
+		csvW.Comma = c.csvDelimiter
+	}
+	if err := csvW.Write(headerRow); err != nil {
+		return err
+	}
+	for _, d := range c.dependencies {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := csvW.Write(csvRecord(d)); err != nil {
+			return err
+		}
+	}
+	csvW.Flush()
+	return csvW.Error()
+}
+
+// csvRecord returns dependency fields in the same order as headerRow
+func csvRecord(d *Repository) []string {
+	return []string{d.Name, d.URL, d.License, d.Version}
+}
+
+// validCSVDelimiter reports whether r can be used as delimiter, the same way csv.Reader validates it
+func validCSVDelimiter(r rune) bool {
+	return r != '"' && r != '\r' && r != '\n' && utf8.ValidRune(r) && r != utf8.RuneError
+}
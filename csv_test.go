@@ -0,0 +1,81 @@
+package glice
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+)
+
+func TestClient_PrintCSV(t *testing.T) {
+	deps := []*Repository{{Name: "github.com/ribice/glice", URL: "https://github.com/ribice/glice", License: "MIT", Version: "v1.0.0"}}
+	tests := map[string]struct {
+		delimiter rune
+		bom       bool
+		want      string
+	}{
+		"default": {
+			want: "Dependency,RepoURL,License,Version\ngithub.com/ribice/glice,https://github.com/ribice/glice,MIT,v1.0.0\n",
+		},
+		"tsv": {
+			delimiter: '\t',
+			want:      "Dependency\tRepoURL\tLicense\tVersion\ngithub.com/ribice/glice\thttps://github.com/ribice/glice\tMIT\tv1.0.0\n",
+		},
+		"bom": {
+			bom:  true,
+			want: "\xEF\xBB\xBFDependency,RepoURL,License,Version\ngithub.com/ribice/glice,https://github.com/ribice/glice,MIT,v1.0.0\n",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Client{format: "csv", output: "stdout", dependencies: deps, csvDelimiter: tt.delimiter, csvBOM: tt.bom}
+			output := &bytes.Buffer{}
+			if err := c.Print(output); err != nil {
+				t.Fatal(err)
+			}
+			if got := output.String(); got != tt.want {
+				t.Errorf("Print() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_PrintCSV_HeaderAlignment(t *testing.T) {
+	d := &Repository{Name: "github.com/ribice/glice", URL: "https://github.com/ribice/glice", Project: "glice", License: "MIT", Version: "v1.0.0"}
+	c := &Client{format: "csv", output: "stdout", dependencies: []*Repository{d}}
+	output := &bytes.Buffer{}
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(output).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected header and one row, got %v", records)
+	}
+	if !reflect.DeepEqual(records[0], headerRow) {
+		t.Errorf("header = %v, want %v", records[0], headerRow)
+	}
+
+	row := map[string]string{}
+	for i, h := range records[0] {
+		row[h] = records[1][i]
+	}
+	want := map[string]string{"Dependency": d.Name, "RepoURL": d.URL, "License": d.License, "Version": d.Version}
+	if !reflect.DeepEqual(row, want) {
+		t.Errorf("row = %v, want %v", row, want)
+	}
+}
+
+func TestNewClient_CSVDelimiter(t *testing.T) {
+	for _, r := range []rune{'\n', '"', -1} {
+		if _, err := NewClient(wd(), WithCSVDelimiter(r)); err == nil {
+			t.Errorf("expected error for delimiter %q", r)
+		}
+	}
+	if _, err := NewClient(wd(), WithCSVDelimiter(';')); err != nil {
+		t.Error(err)
+	}
+}
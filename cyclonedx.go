@@ -0,0 +1,65 @@
+package glice
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"time"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+)
+
+// cdxSpecVersion is CycloneDX spec version of printed BOMs
+const cdxSpecVersion = cdx.SpecVersion1_5
+
+func (c *Client) printCycloneDXJSON(writeTo io.Writer) error {
+	return cdx.NewBOMEncoder(writeTo, cdx.BOMFileFormatJSON).EncodeVersion(c.cycloneDXBOM(), cdxSpecVersion)
+}
+
+func (c *Client) printCycloneDXXML(writeTo io.Writer) error {
+	enc := cdx.NewBOMEncoder(writeTo, cdx.BOMFileFormatXML).SetPretty(true)
+	if err := enc.EncodeVersion(c.cycloneDXBOM(), cdxSpecVersion); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(writeTo, "\n")
+	return err
+}
+
+func (c *Client) cycloneDXBOM() *cdx.BOM {
+	name := c.moduleName()
+	bom := cdx.NewBOM()
+	bom.SerialNumber = cdxSerialNumber()
+	bom.Metadata = &cdx.Metadata{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Tools: &cdx.ToolsChoice{
+			Components: &[]cdx.Component{{Type: cdx.ComponentTypeApplication, Name: "glice"}},
+		},
+		Component: &cdx.Component{BOMRef: "pkg:golang/" + name, Type: cdx.ComponentTypeApplication, Name: name, PackageURL: "pkg:golang/" + name},
+	}
+
+	components := []cdx.Component{}
+	for _, d := range c.dependencies {
+		purl := d.PURL
+		if purl == "" {
+			purl = buildPURL(d)
+		}
+		cmp := cdx.Component{BOMRef: purl, Type: cdx.ComponentTypeLibrary, Name: d.Name, Version: d.Version, PackageURL: purl}
+		if l := spdxLicense(d); l != spdxNoAssertion {
+			cmp.Licenses = &cdx.Licenses{{Expression: l}}
+		}
+		components = append(components, cmp)
+	}
+	bom.Components = &components
+
+	return bom
+}
+
+// cdxSerialNumber returns random RFC 4122 version 4 UUID URN
+func cdxSerialNumber() string {
+	var u [16]byte
+	rand.Read(u[:])
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
+}
@@ -0,0 +1,94 @@
+package glice
+
+import (
+	"bytes"
+	"encoding/xml"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+)
+
+func TestClient_PrintCycloneDXJSON(t *testing.T) {
+	c := &Client{path: wd(), format: "cyclonedx-json", output: "stdout", dependencies: []*Repository{
+		{Name: "github.com/ribice/glice", Host: "github.com", Author: "ribice", Project: "glice", License: "MIT", Version: "v1.0.0"},
+		{Name: "golang.org/x/mod", License: "Other", Version: "v0.8.0"},
+	}}
+
+	output := &bytes.Buffer{}
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+
+	var got cdx.BOM
+	if err := cdx.NewBOMDecoder(output, cdx.BOMFileFormatJSON).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.BOMFormat != "CycloneDX" || got.SpecVersion != cdx.SpecVersion1_5 || got.Version != 1 {
+		t.Errorf("unexpected BOM header: %+v", got)
+	}
+	if !regexp.MustCompile(`^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(got.SerialNumber) {
+		t.Errorf("invalid serial number %s", got.SerialNumber)
+	}
+	if got.Metadata.Component.Name != "github.com/ribice/glice/v2" || got.Metadata.Component.Type != "application" {
+		t.Errorf("unexpected root component: %+v", got.Metadata.Component)
+	}
+
+	if tools := got.Metadata.Tools; tools == nil || tools.Components == nil || (*tools.Components)[0].Name != "glice" {
+		t.Errorf("expected glice tool, got %+v", got.Metadata.Tools)
+	}
+
+	want := []cdx.Component{{
+		BOMRef: "pkg:golang/github.com/ribice/glice@v1.0.0", Type: cdx.ComponentTypeLibrary, Name: "github.com/ribice/glice", Version: "v1.0.0",
+		PackageURL: "pkg:golang/github.com/ribice/glice@v1.0.0", Licenses: &cdx.Licenses{{Expression: "MIT"}},
+	}, {
+		BOMRef: "pkg:golang/golang.org/x/mod@v0.8.0", Type: cdx.ComponentTypeLibrary, Name: "golang.org/x/mod", Version: "v0.8.0",
+		PackageURL: "pkg:golang/golang.org/x/mod@v0.8.0",
+	}}
+	if got.Components == nil || !reflect.DeepEqual(*got.Components, want) {
+		t.Errorf("components = %+v, want %+v", got.Components, want)
+	}
+}
+
+func TestClient_PrintCycloneDXXML(t *testing.T) {
+	c := &Client{path: wd(), format: "cyclonedx-xml", output: "stdout", dependencies: []*Repository{
+		{Name: "github.com/ribice/glice", Host: "github.com", Author: "ribice", Project: "glice", License: "MIT", Version: "v1.0.0"},
+	}}
+
+	output := &bytes.Buffer{}
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(output.Bytes(), []byte(xml.Header)) {
+		t.Error("expected XML header")
+	}
+	for _, want := range []string{
+		`<bom xmlns="http://cyclonedx.org/schema/bom/1.5" serialNumber="urn:uuid:`,
+		`<licenses>`,
+		`<expression>MIT</expression>`,
+	} {
+		if !strings.Contains(output.String(), want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+
+	var got cdx.BOM
+	if err := cdx.NewBOMDecoder(output, cdx.BOMFileFormatXML).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.XMLNS != "http://cyclonedx.org/schema/bom/1.5" || got.Version != 1 || !strings.HasPrefix(got.SerialNumber, "urn:uuid:") {
+		t.Errorf("unexpected BOM: %+v", got)
+	}
+	if tools := got.Metadata.Tools; tools == nil || tools.Components == nil || (*tools.Components)[0].Name != "glice" {
+		t.Errorf("expected glice tool, got %+v", got.Metadata.Tools)
+	}
+	want := []cdx.Component{{
+		BOMRef: "pkg:golang/github.com/ribice/glice@v1.0.0", Type: cdx.ComponentTypeLibrary, Name: "github.com/ribice/glice", Version: "v1.0.0",
+		PackageURL: "pkg:golang/github.com/ribice/glice@v1.0.0", Licenses: &cdx.Licenses{{Expression: "MIT"}},
+	}}
+	if got.Components == nil || !reflect.DeepEqual(*got.Components, want) {
+		t.Errorf("components = %+v, want %+v", got.Components, want)
+	}
+}
@@ -0,0 +1,66 @@
+package glice
+
+import (
+	"context"
+
+	"github.com/ribice/glice/v2/mod"
+)
+
+// Diff returns dependencies added to and removed from go.mod at newPath compared to go.mod at oldPath.
+// Dependencies whose version changed are reported both as removed (old version) and added (new version).
+// Licenses are not fetched.
+func Diff(oldPath, newPath string, indirect bool) (added, removed []*Repository, err error) {
+	oldRepos, err := ListRepositories(oldPath, indirect)
+	if err != nil {
+		return nil, nil, err
+	}
+	newRepos, err := ListRepositories(newPath, indirect)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	added, removed = diffRepositories(oldRepos, newRepos)
+	return added, removed, nil
+}
+
+// DiffWith compares parsed dependencies of the client with go.mod at otherPath. Licenses are fetched only
+// for added dependencies, removed ones are taken from already parsed dependencies. Errors of fetching
+// added dependencies are added to Errors of the parsing.
+func (c *Client) DiffWith(otherPath string, indirect bool) (added, removed []*Repository, err error) {
+	modules, err := mod.Parse(otherPath, indirect, mod.WithLogger(c.log()))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	added, removed = diffRepositories(c.dependencies, toRepositories(modules, c.repoCache, c.forgeHosts()))
+	if len(added) > 0 {
+		keys, err := c.apiKeysFor(false)
+		if err != nil {
+			return nil, nil, err
+		}
+		added = c.addLicenses(context.Background(), added, keys, false)
+	}
+	return added, removed, nil
+}
+
+func diffRepositories(oldRepos, newRepos []*Repository) (added, removed []*Repository) {
+	key := func(r *Repository) string { return r.Name + "@" + r.Version }
+
+	oldSet := make(map[string]bool, len(oldRepos))
+	for _, r := range oldRepos {
+		oldSet[key(r)] = true
+	}
+	newSet := make(map[string]bool, len(newRepos))
+	for _, r := range newRepos {
+		newSet[key(r)] = true
+		if !oldSet[key(r)] {
+			added = append(added, r)
+		}
+	}
+	for _, r := range oldRepos {
+		if !newSet[key(r)] {
+			removed = append(removed, r)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,84 @@
+package glice
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/mod/module"
+)
+
+func writeGoMod(t *testing.T, requires string) string {
+	t.Helper()
+	dir := t.TempDir()
+	gomod := "module example.com/a\n\ngo 1.18\n\nrequire (\n" + requires + ")\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func repoNames(repos []*Repository) []string {
+	var names []string
+	for _, r := range repos {
+		names = append(names, r.Name+"@"+r.Version)
+	}
+	return names
+}
+
+func TestDiff(t *testing.T) {
+	oldPath := writeGoMod(t, "\tgithub.com/ribice/kiss v1.0.0\n\tgithub.com/fatih/color v1.16.0\n\tgithub.com/some/removed v0.1.0\n")
+	newPath := writeGoMod(t, "\tgithub.com/ribice/kiss v1.0.0\n\tgithub.com/fatih/color v1.17.0\n\tgithub.com/some/added v0.2.0\n")
+
+	added, removed, err := Diff(oldPath, newPath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"github.com/fatih/color@v1.17.0", "github.com/some/added@v0.2.0"}; !reflect.DeepEqual(repoNames(added), want) {
+		t.Errorf("added = %v, want %v", repoNames(added), want)
+	}
+	if want := []string{"github.com/fatih/color@v1.16.0", "github.com/some/removed@v0.1.0"}; !reflect.DeepEqual(repoNames(removed), want) {
+		t.Errorf("removed = %v, want %v", repoNames(removed), want)
+	}
+
+	if _, _, err := Diff(oldPath, "invalid", false); err == nil {
+		t.Error("expected error for invalid path")
+	}
+}
+
+func TestClient_DiffWith(t *testing.T) {
+	kiss := &Repository{Name: "github.com/ribice/kiss", Version: "v1.0.0", License: "MIT"}
+	gpl := &Repository{Name: "github.com/some/gpl", Version: "v0.1.0", License: "GPL-3.0"}
+	parseErr := errors.New("fetching github.com/some/gpl: not found")
+	c := &Client{
+		dependencies: []*Repository{kiss, gpl},
+		cache:        &diskCache{dir: t.TempDir()},
+		errors:       []error{parseErr},
+		latencies:    map[string]time.Duration{kiss.Name: time.Second},
+	}
+
+	// added dependency is served from cache, so no network calls are made
+	added := *getRepository(module.Version{Path: "github.com/some/added", Version: "v0.2.0"}, nil, forgeHosts{})
+	added.License = "Apache-2.0"
+	if err := c.cache.put(c.cache.path(&added), &added); err != nil {
+		t.Fatal(err)
+	}
+
+	newPath := writeGoMod(t, "\tgithub.com/ribice/kiss v1.0.0\n\tgithub.com/some/added v0.2.0\n")
+	gotAdded, gotRemoved, err := c.DiffWith(newPath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gotAdded) != 1 || gotAdded[0].Name != added.Name || gotAdded[0].License != "Apache-2.0" {
+		t.Errorf("added = %+v, want dependency with fetched license", gotAdded)
+	}
+	if !reflect.DeepEqual(gotRemoved, []*Repository{gpl}) {
+		t.Errorf("removed = %v, want %v", repoNames(gotRemoved), repoNames([]*Repository{gpl}))
+	}
+	if !reflect.DeepEqual(c.Errors(), []error{parseErr}) || c.latencies[kiss.Name] != time.Second {
+		t.Errorf("expected errors and latencies of parsing to be kept, got %v, %v", c.Errors(), c.latencies)
+	}
+}
@@ -0,0 +1,76 @@
+package glice
+
+import (
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// dotColors maps terminal license colors to Graphviz color names
+var dotColors = map[color.Attribute]string{
+	color.FgRed:       "red3",
+	color.FgGreen:     "green4",
+	color.FgYellow:    "gold3",
+	color.FgBlue:      "blue",
+	color.FgMagenta:   "magenta3",
+	color.FgCyan:      "cyan4",
+	color.FgHiRed:     "orangered",
+	color.FgHiGreen:   "limegreen",
+	color.FgHiYellow:  "goldenrod",
+	color.FgHiBlue:    "dodgerblue",
+	color.FgHiMagenta: "violet",
+	color.FgHiCyan:    "darkturquoise",
+	color.FgHiWhite:   "gray50",
+}
+
+// dotCategories is order of license category subgraphs
+var dotCategories = []string{
+	CategoryPermissive, CategoryWeakCopyleft, CategoryStrongCopyleft, CategoryNetworkCopyleft, CategoryPublicDomain, CategoryUnknown,
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
+
+// printDOT writes dependencies as Graphviz graph, with a node per dependency grouped into
+// subgraphs by license category. Nodes have the same colors as licenses in terminal.
+func (c *Client) printDOT(writeTo io.Writer) error {
+	byCategory := map[string][]*Repository{}
+	for _, d := range c.dependencies {
+		cat := d.Category
+		if cat == "" {
+			cat = LicenseCategory(d.License)
+		}
+		byCategory[cat] = append(byCategory[cat], d)
+	}
+
+	w := &errWriter{w: writeTo}
+	w.printf("digraph dependencies {\n")
+	w.printf("\tlabel=%s;\n", dotQuote(c.moduleName()+" dependency licenses"))
+	w.printf("\tnode [shape=box, style=\"rounded,bold\"];\n")
+	for _, cat := range dotCategories {
+		deps := byCategory[cat]
+		if len(deps) < 1 {
+			continue
+		}
+		w.printf("\tsubgraph %s {\n", dotQuote("cluster_"+cat))
+		w.printf("\t\tlabel=%s;\n", dotQuote(cat))
+		for _, d := range deps {
+			license := d.License
+			if license == "" {
+				license = unknownLicense
+			}
+			clr, ok := dotColors[getLicenseColor(d.License)]
+			if !ok {
+				clr = "black"
+			}
+			w.printf("\t\t%s [label=%s, color=%s, fontcolor=%s];\n", dotQuote(d.Name), dotQuote(d.Name+"\n"+license), clr, clr)
+		}
+		w.printf("\t}\n")
+	}
+	w.printf("}\n")
+	return w.err
+}
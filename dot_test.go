@@ -0,0 +1,40 @@
+package glice
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestClient_PrintDOT(t *testing.T) {
+	c := &Client{format: "dot", path: "/tmp/glice", dependencies: []*Repository{
+		{Name: "github.com/ribice/glice", License: "MIT", Category: CategoryPermissive},
+		{Name: "github.com/some/gpl", License: "GPL-3.0"},
+		{Name: `example.com/"quoted"`},
+	}}
+
+	output := &bytes.Buffer{}
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `digraph dependencies {
+	label="glice dependency licenses";
+	node [shape=box, style="rounded,bold"];
+	subgraph "cluster_permissive" {
+		label="permissive";
+		"github.com/ribice/glice" [label="github.com/ribice/glice\nMIT", color=green4, fontcolor=green4];
+	}
+	subgraph "cluster_strong-copyleft" {
+		label="strong-copyleft";
+		"github.com/some/gpl" [label="github.com/some/gpl\nGPL-3.0", color=violet, fontcolor=violet];
+	}
+	subgraph "cluster_unknown" {
+		label="unknown";
+		"example.com/\"quoted\"" [label="example.com/\"quoted\"\nUnknown", color=gold3, fontcolor=gold3];
+	}
+}
+`
+	if got := output.String(); got != want {
+		t.Errorf("Print() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,113 @@
+package glice
+
+import (
+	"strings"
+)
+
+// DualLicensedDependencies returns dependencies available under more than one license
+func (c *Client) DualLicensedDependencies() []*Repository {
+	var dual []*Repository
+	for _, d := range c.dependencies {
+		if len(d.AlternateLicenses) > 0 {
+			dual = append(dual, d)
+		}
+	}
+	return dual
+}
+
+// applyDualLicenses sets AlternateLicenses of repositories whose license key is an "or" expression, or
+// whose license text states a choice between multiple licenses. With WithPreferredSPDX, License is set
+// to the preferred license or to the least restrictive one when the preferred isn't available, otherwise
+// the fetched license is kept. Multiple licenses in text without a choice are set as AdditionalLicenses
+// and never replace the fetched license.
+func (c *Client) applyDualLicenses(repos []*Repository) {
+	for _, r := range repos {
+		keys, choice := dualLicenseKeys(r)
+		if len(keys) < 2 {
+			continue
+		}
+
+		var primary string
+		for _, k := range keys {
+			if strings.EqualFold(NormalizeSPDX(k), r.LicenseSPDX) {
+				primary = k
+			}
+		}
+		if !choice {
+			r.AdditionalLicenses = otherLicenses(keys, primary)
+			continue
+		}
+		if primary == "" && isLicenseExpression(r.LicenseSPDX) {
+			// fetched license is the expression itself, so its first license is used
+			primary = keys[0]
+		}
+		if c.preferredSPDX != "" {
+			primary = preferLicense(keys, c.preferredSPDX)
+		}
+
+		if primary != "" {
+			setLicense(r, primary)
+			classifyLicense(r)
+		}
+		r.AlternateLicenses = otherLicenses(keys, primary)
+	}
+}
+
+// otherLicenses returns names of licenses of keys other than primary
+func otherLicenses(keys []string, primary string) []string {
+	var names []string
+	for _, k := range keys {
+		if k != primary {
+			names = append(names, licenseName(k))
+		}
+	}
+	return names
+}
+
+// isLicenseExpression reports whether SPDX identifier is an "or" expression, e.g. "MIT OR Apache-2.0"
+func isLicenseExpression(spdxID string) bool {
+	return strings.Contains(strings.ToLower(spdxID), " or ")
+}
+
+// dualLicenseKeys returns lowercase keys of licenses of r and whether r can be used under any one of
+// them. Keys are taken from license key if it is an "or" expression (e.g. "mit or apache-2.0"),
+// otherwise detected from license text, which has to state the choice, see statesLicenseChoice.
+func dualLicenseKeys(r *Repository) (keys []string, choice bool) {
+	if isLicenseExpression(r.LicenseSPDX) {
+		for _, k := range strings.Split(strings.ToLower(r.LicenseSPDX), " or ") {
+			keys = append(keys, strings.Trim(k, " ()"))
+		}
+		return keys, true
+	}
+	if r.Text == "" {
+		return nil, false
+	}
+	text, err := r.LicenseText()
+	if err != nil {
+		return nil, false
+	}
+	return detectLicenseKeys(text), statesLicenseChoice(text)
+}
+
+// preferLicense returns preferred license if it's one of keys, otherwise the least restrictive key
+func preferLicense(keys []string, preferred string) string {
+	best := keys[0]
+	for _, k := range keys {
+		if strings.EqualFold(k, preferred) {
+			return k
+		}
+		if LicenseRiskScore(k) < LicenseRiskScore(best) {
+			best = k
+		}
+	}
+	return best
+}
+
+// licenseName returns name of license key, which is its SPDX identifier, or Other for licenses
+// that couldn't be identified
+func licenseName(key string) string {
+	if key == "other" {
+		return licenseCol[key].name
+	}
+	return NormalizeSPDX(key)
+}
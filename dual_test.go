@@ -0,0 +1,73 @@
+package glice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClient_ApplyDualLicenses(t *testing.T) {
+	dualText := "This project is dual licensed, you may use it under either license at your option.\n\nApache License\nVersion 2.0, January 2004\n\nPermission is hereby granted, free of charge, to any person obtaining a copy"
+	bundledText := "GNU General Public License\nVersion 3, 29 June 2007\n\nThird-party code in vendor/ is licensed as follows.\n\nPermission is hereby granted, free of charge, to any person obtaining a copy"
+	tests := map[string]struct {
+		repo           Repository
+		preferred      string
+		wantLicense    string
+		wantAlternate  []string
+		wantAdditional []string
+	}{
+		"fetched license kept": {
+			repo:          Repository{License: "MIT", LicenseSPDX: "mit", Text: dualText},
+			wantLicense:   "MIT",
+			wantAlternate: []string{"Apache-2.0"},
+		},
+		"undetected license kept": {
+			repo:          Repository{License: "Other", Text: dualText},
+			wantLicense:   "Other",
+			wantAlternate: []string{"Apache-2.0", "MIT"},
+		},
+		"bundled notice": {
+			repo:           Repository{License: "GPL-3.0-only", LicenseSPDX: "GPL-3.0-only", Text: bundledText},
+			preferred:      "MIT",
+			wantLicense:    "GPL-3.0-only",
+			wantAdditional: []string{"MIT"},
+		},
+		"or expression": {
+			repo:          Repository{License: "MIT or Apache-2.0", LicenseSPDX: "MIT OR Apache-2.0"},
+			wantLicense:   "MIT",
+			wantAlternate: []string{"Apache-2.0"},
+		},
+		"preferred": {
+			repo:          Repository{License: "MIT", LicenseSPDX: "mit", Text: dualText},
+			preferred:     "Apache-2.0",
+			wantLicense:   "Apache-2.0",
+			wantAlternate: []string{"MIT"},
+		},
+		"preferred unavailable selects least restrictive": {
+			repo:          Repository{License: "Apache-2.0", LicenseSPDX: "apache-2.0", Text: dualText},
+			preferred:     "BSD-3-Clause",
+			wantLicense:   "MIT",
+			wantAlternate: []string{"Apache-2.0"},
+		},
+		"single license": {
+			repo:        Repository{License: "MIT", LicenseSPDX: "mit", Text: "Permission is hereby granted, free of charge, to any person obtaining a copy"},
+			wantLicense: "MIT",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := tt.repo
+			c := &Client{preferredSPDX: tt.preferred, dependencies: []*Repository{&r}}
+			WithColor(false)(c)
+			c.applyDualLicenses(c.dependencies)
+			if r.License != tt.wantLicense || !reflect.DeepEqual(r.AlternateLicenses, tt.wantAlternate) {
+				t.Errorf("got license %s and alternates %v, want %s and %v", r.License, r.AlternateLicenses, tt.wantLicense, tt.wantAlternate)
+			}
+			if !reflect.DeepEqual(r.AdditionalLicenses, tt.wantAdditional) {
+				t.Errorf("got additional licenses %v, want %v", r.AdditionalLicenses, tt.wantAdditional)
+			}
+			if got := len(c.DualLicensedDependencies()); got != min(len(tt.wantAlternate), 1) {
+				t.Errorf("DualLicensedDependencies() returned %d dependencies", got)
+			}
+		})
+	}
+}
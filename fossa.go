@@ -0,0 +1,35 @@
+package glice
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// fossaDependency is dependency in the format FOSSA CLI imports
+type fossaDependency struct {
+	Package  string   `json:"Package"`
+	Revision string   `json:"Revision"`
+	Locator  string   `json:"Locator"`
+	Licenses []string `json:"Licenses"`
+}
+
+// fossaLocator returns FOSSA locator of Go module r, go+<module-path>$<version>
+func fossaLocator(r *Repository) string {
+	return "go+" + r.Name + "$" + r.Version
+}
+
+// printFOSSA writes dependencies as JSON array FOSSA can import. FOSSA license identifiers match
+// SPDX ones glice uses, unknown licenses are left out.
+func (c *Client) printFOSSA(writeTo io.Writer) error {
+	deps := make([]fossaDependency, len(c.dependencies))
+	for i, d := range c.dependencies {
+		deps[i] = fossaDependency{Package: d.Name, Revision: d.Version, Locator: fossaLocator(d), Licenses: []string{}}
+		if l := spdxLicense(d); l != spdxNoAssertion {
+			deps[i].Licenses = append(deps[i].Licenses, l)
+		}
+	}
+
+	enc := json.NewEncoder(writeTo)
+	enc.SetIndent(c.jsonPrefix, c.jsonIndent)
+	return enc.Encode(deps)
+}
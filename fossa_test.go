@@ -0,0 +1,24 @@
+package glice
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestClient_PrintFOSSA(t *testing.T) {
+	c := &Client{format: "fossa", dependencies: []*Repository{
+		{Name: "github.com/ribice/glice", License: "MIT", LicenseSPDX: "MIT", Version: "v1.0.0"},
+		{Name: "golang.org/x/mod", License: "Other", Version: "v0.8.0"},
+	}}
+
+	output := &bytes.Buffer{}
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `[{"Package":"github.com/ribice/glice","Revision":"v1.0.0","Locator":"go+github.com/ribice/glice$v1.0.0","Licenses":["MIT"]},` +
+		`{"Package":"golang.org/x/mod","Revision":"v0.8.0","Locator":"go+golang.org/x/mod$v0.8.0","Licenses":[]}]` + "\n"
+	if got := output.String(); got != want {
+		t.Errorf("Print() = %s, want %s", got, want)
+	}
+}
@@ -2,147 +2,922 @@ package glice
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/csv"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"text/template"
+	"time"
 
-	"github.com/fatih/color"
-	"github.com/olekukonko/tablewriter"
+	"github.com/schollz/progressbar/v3"
 	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
 
 	"github.com/ribice/glice/v2/mod"
 )
 
 var (
-	// ErrNoGoMod is returned when path doesn't contain go.mod file
+	// ErrNoGoMod is returned when path contains neither go.mod nor go.work file
 	ErrNoGoMod = errors.New("no go.mod file present")
 
 	// ErrNoAPIKey is returned when thanks flag is enabled without providing GITHUB_API_KEY env variable
 	ErrNoAPIKey = errors.New("cannot use thanks feature without github api key")
 
 	validFormats = map[string]bool{
-		"table": true,
-		"json":  true,
-		"csv":   true,
+		"table":          true,
+		"json":           true,
+		"csv":            true,
+		"spdx-json":      true,
+		"spdx-tv":        true,
+		"html":           true,
+		"markdown":       true,
+		"yaml":           true,
+		"template":       true,
+		"xml":            true,
+		"junit":          true,
+		"cyclonedx-json": true,
+		"cyclonedx-xml":  true,
+		"sarif":          true,
+		"ndjson":         true,
+		"dot":            true,
+		"compat":         true,
+		"fossa":          true,
+		"benchmark":      true,
 	}
 
 	// validOutputs to print to
 	validOutputs = map[string]bool{
 		"stdout": true,
 		"file":   true,
+		// both writes to stdout and file at once
+		"both": true,
+	}
+
+	// formatExtensions are extensions of files written with file output
+	formatExtensions = map[string]string{
+		"table":          "txt",
+		"json":           "json",
+		"ndjson":         "ndjson",
+		"csv":            "csv",
+		"spdx-json":      "spdx.json",
+		"spdx-tv":        "spdx",
+		"html":           "html",
+		"markdown":       "md",
+		"yaml":           "yaml",
+		"template":       "txt",
+		"xml":            "xml",
+		"junit":          "junit.xml",
+		"cyclonedx-json": "cdx.json",
+		"cyclonedx-xml":  "cdx.xml",
+		"dot":            "dot",
+		"compat":         "txt",
+		"fossa":          "fossa.json",
+		"benchmark":      "txt",
+		"sarif":          "sarif",
 	}
 )
 
 type Client struct {
+	// AllowedLicenses, if not empty, lists the only licenses dependencies may use
+	AllowedLicenses []string
+	// BlockedLicenses lists licenses dependencies must not use
+	BlockedLicenses []string
+	// LicenseOverrides maps import paths to licenses used instead of the fetched ones,
+	// e.g. for dependencies whose license can't be detected
+	LicenseOverrides map[string]string
+
 	dependencies []*Repository
 	path         string
 	format       string
 	output       string
+	outputs      []string
+	outputWriter io.Writer
+	// outputFile is path of the file written with file and both outputs
+	outputFile   string
+	templateText string
+	template     *template.Template
+	// licenseDir is directory WriteLicensesToFile writes to, licenses directory in path by default
+	licenseDir          string
+	licenseFileTmplText string
+	licenseFileTmpl     *template.Template
+	concurrency         int
+	timeout             time.Duration
+	logger              *slog.Logger
+	httpClient          *http.Client
+	apiKeys             map[string]string
+	cache               *diskCache
+	// repoCache holds repositories resolved from import paths of modules not hosted on known hosts
+	repoCache *repoCache
+	retry     retryPolicy
+	// breakerThreshold and breakerOpen configure circuit breaker of each host, see WithCircuitBreaker
+	breakerThreshold int
+	breakerOpen      time.Duration
+	workspace        bool
+	ignore           []string
+	noticeFileName   string
+	columns          []string
+	failOnCopyleft   bool
+	failOnUnknown    bool
+	githubBaseURL    string
+	gitlabHosts      []string
+	giteaHosts       []string
+	// legacyJSON prints json format as array of dependencies instead of object with summary
+	legacyJSON bool
+	// preferredSPDX is license selected for dual licensed dependencies
+	preferredSPDX string
+	// presetDependencies is set when dependencies were set with WithDependencies instead of parsing go.mod
+	presetDependencies bool
+	// interactiveAuth obtains GitHub token with OAuth2 device flow when API key isn't set, see WithInteractiveAuth
+	interactiveAuth bool
+	// recursive parses all go.mod files in directory tree of path, see NewClientForRoot
+	recursive bool
+	// indirect includes indirect dependencies when parsing them, see WithIndirect
+	indirect bool
+	// pageSize is number of table rows printed to terminal before waiting for Enter, 0 disables paging
+	pageSize int
+	// dryRun lists dependencies from go.mod without any network calls, see WithDryRun
+	dryRun bool
+	// excludeStdlib skips dependencies that are part of Go project, see Repository.IsStdlib
+	excludeStdlib bool
+	// includeToolchain adds toolchain from go.mod toolchain directive to dependencies
+	includeToolchain bool
+	csvDelimiter     rune
+	csvBOM           bool
+	jsonPrefix       string
+	jsonIndent       string
+	summary          bool
+	vendor           bool
+	vendorDir        string
+	progress         bool
+	// color is set by WithColor, colors are detected from NO_COLOR and output when nil
+	color          *bool
+	graphQL        bool
+	abandoned      bool
+	abandonedYears int
+	// allLicenseFiles fetches all license and notice files of GitHub repositories
+	allLicenseFiles bool
+	// taggedLicenses reads licenses of GitHub repositories at the tag of their version
+	taggedLicenses bool
+	sortField      string
+	sortDirection  string
+	// compatRules extend built-in license compatibility rules
+	compatRules   map[string]map[string]bool
+	webhookURL    string
+	webhookEvents []string
+	// serveTTL is how long Serve reuses parsed dependencies
+	serveTTL time.Duration
+	// errors holds per-dependency errors of the last license fetching
+	errors []error
+	// latencies are durations of license fetching of the last run per import path
+	latencies map[string]time.Duration
 }
 
-func NewClient(path, format, output string) (*Client, error) {
-	if !validFormats[format] {
-		return nil, fmt.Errorf("invalid format provided (%s) - allowed ones are [table, json, csv]", output)
+// toolchainModule is module path of Go toolchain pseudo-dependency added with WithIncludeToolchain
+const toolchainModule = "golang.org/toolchain"
+
+const (
+	defaultConcurrency = 5
+	defaultTimeout     = 10 * time.Second
+)
+
+// NewClient creates a client for go.mod located at path.
+// Unless configured by options, it prints a table to stdout.
+func NewClient(path string, opts ...Option) (*Client, error) {
+	c := &Client{
+		path:        path,
+		format:      "table",
+		output:      "stdout",
+		concurrency: defaultConcurrency,
+		timeout:     defaultTimeout,
+		logger:      slog.Default(),
+		apiKeys:     map[string]string{},
+		progress:    term.IsTerminal(int(os.Stderr.Fd())),
+		repoCache:   newRepoCache(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.repoCache.offline = c.dryRun
+	c.repoCache.logger = c.log()
+
+	if !validFormats[c.format] {
+		return nil, fmt.Errorf("invalid format provided (%s) - allowed ones are [%s]", c.format, strings.Join(keys(validFormats), ", "))
+	}
+
+	if len(c.outputs) < 1 {
+		c.outputs = []string{c.output}
+	}
+	for _, o := range c.outputs {
+		if !validOutputs[o] {
+			return nil, fmt.Errorf("invalid output provided (%s) - allowed ones are [%s]", o, strings.Join(keys(validOutputs), ", "))
+		}
+	}
+	c.output = c.outputs[0]
+
+	if c.breakerThreshold < 0 {
+		return nil, fmt.Errorf("invalid circuit breaker threshold provided (%d) - has to be positive", c.breakerThreshold)
+	}
+
+	if c.pageSize < 0 {
+		return nil, fmt.Errorf("invalid page size provided (%d) - has to be positive", c.pageSize)
+	}
+
+	for _, col := range c.columns {
+		if _, ok := tableColumns[col]; !ok {
+			return nil, fmt.Errorf("invalid column provided (%s) - allowed ones are [%s]", col, strings.Join(tableColumnNames(), ", "))
+		}
+	}
+
+	if c.sortField != "" {
+		if _, ok := sortFields[c.sortField]; !ok {
+			return nil, fmt.Errorf("invalid sort field provided (%s) - allowed ones are [%s]", c.sortField, strings.Join(keys(sortFields), ", "))
+		}
+		if !validSortDirections[c.sortDirection] {
+			return nil, fmt.Errorf("invalid sort direction provided (%s) - allowed ones are [asc, desc]", c.sortDirection)
+		}
+	}
+
+	if c.csvDelimiter != 0 && !validCSVDelimiter(c.csvDelimiter) {
+		return nil, fmt.Errorf("invalid csv delimiter provided (%q)", c.csvDelimiter)
+	}
+
+	for _, e := range c.webhookEvents {
+		if !webhookEvents[e] {
+			return nil, fmt.Errorf("invalid webhook event provided (%s) - allowed ones are [%s]", e, strings.Join(keys(webhookEvents), ", "))
+		}
 	}
 
-	if !validOutputs[output] {
-		return nil, fmt.Errorf("invalid output provided (%s) - allowed ones are [stdout, file]", output)
+	if c.concurrency < 1 {
+		return nil, fmt.Errorf("invalid concurrency provided (%d) - it has to be at least 1", c.concurrency)
 	}
 
-	if !mod.Exists(path) {
+	hasGoMod, hasGoWork := mod.HasModule(path)
+	// root of directory tree parsed recursively doesn't have to be a module itself, and dependencies
+	// set with WithDependencies don't need one at all
+	if !hasGoMod && !hasGoWork && !c.recursive && !c.presetDependencies {
 		return nil, ErrNoGoMod
 	}
+	// workspace without go.mod in its root can only be parsed in workspace mode
+	if !hasGoMod {
+		c.workspace = true
+	}
+
+	if c.licenseFileTmplText != "" {
+		t, err := template.New("license-file").Parse(c.licenseFileTmplText)
+		if err != nil {
+			return nil, fmt.Errorf("invalid license file template provided: %w", err)
+		}
+		c.licenseFileTmpl = t
+	}
+
+	if c.format == "template" {
+		t, err := parseTemplate(c.templateText)
+		if err != nil {
+			return nil, err
+		}
+		c.template = t
+	}
+
+	return c, nil
+}
+
+// NewClientForRoot creates a client parsing dependencies of all modules in directory tree rooted at
+// root, e.g. of a monorepo, see mod.FindAll. Current directory is used when root is empty.
+// Modules found in the tree aren't reported as dependencies of each other.
+func NewClientForRoot(root string, opts ...Option) (*Client, error) {
+	if root == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		root = wd
+	}
+	return NewClient(root, append(opts, func(c *Client) { c.recursive = true })...)
+}
 
-	return &Client{path: path, format: format, output: output}, nil
+// NewClientLegacy creates a client using positional format and output arguments.
+//
+// Deprecated: use NewClient with WithFormat and WithOutput options instead.
+func NewClientLegacy(path, format, output string, opts ...Option) (*Client, error) {
+	return NewClient(path, append([]Option{WithFormat(format), WithOutput(output)}, opts...)...)
 }
 
 func (c *Client) ParseDependencies(includeIndirect, thanks bool) error {
-	githubAPIKey := os.Getenv("GITHUB_API_KEY")
-	if thanks && githubAPIKey == "" {
+	return c.parseDependencies(context.Background(), includeIndirect, thanks)
+}
+
+// parseDependencies is ParseDependencies fetching licenses until ctx is done
+func (c *Client) parseDependencies(ctx context.Context, includeIndirect, thanks bool) error {
+	includeIndirect = includeIndirect || c.indirect
+	if c.workspace && mod.ExistsWork(c.path) {
+		return c.parseWorkspaceDependencies(ctx, includeIndirect, thanks)
+	}
+
+	keys, err := c.apiKeysFor(thanks)
+	if err != nil {
+		return err
+	}
+	if thanks && !c.dryRun && keys["github.com"] == "" {
 		return ErrNoAPIKey
 	}
-	repos, err := ListRepositories(c.path, includeIndirect)
+	parse := mod.Parse
+	if c.recursive {
+		parse = mod.ParseAll
+	}
+	modules, err := parse(c.path, includeIndirect, mod.WithLogger(c.log()))
 	if err != nil {
 		return err
 	}
 
-	log.Printf("Found %d dependencies", len(repos))
+	return c.fetchLicenses(ctx, c.listRepositories(modules), keys, thanks)
+}
+
+// ParseWorkspaceDependencies parses dependencies of all modules used by go.work
+func (c *Client) ParseWorkspaceDependencies(includeIndirect, thanks bool) error {
+	return c.parseWorkspaceDependencies(context.Background(), includeIndirect, thanks)
+}
 
-	ctx := context.Background()
-	gitCl := newGitClient(ctx, map[string]string{"github.com": githubAPIKey}, thanks)
-	sem := make(chan struct{}, 5)
-	var wg sync.WaitGroup
+func (c *Client) parseWorkspaceDependencies(ctx context.Context, includeIndirect, thanks bool) error {
+	includeIndirect = includeIndirect || c.indirect
+	keys, err := c.apiKeysFor(thanks)
+	if err != nil {
+		return err
+	}
+	if thanks && !c.dryRun && keys["github.com"] == "" {
+		return ErrNoAPIKey
+	}
+	modules, err := mod.ParseWork(c.path, includeIndirect, mod.WithLogger(c.log()))
+	if err != nil {
+		return err
+	}
+
+	return c.fetchLicenses(ctx, c.listRepositories(modules), keys, thanks)
+}
+
+func (c *Client) fetchLicenses(ctx context.Context, repos []*Repository, keys map[string]string, thanks bool) error {
+	if c.dryRun {
+		c.errors, c.latencies = nil, nil
+		c.dependencies = c.dedupe(c.filterDependencies(repos))
+	} else {
+		c.dependencies = c.resolveLicenses(ctx, repos, keys, thanks)
+	}
+	if c.includeToolchain && !c.excludeStdlib {
+		tc, err := c.toolchainDependency()
+		if err != nil {
+			return err
+		}
+		if tc != nil {
+			c.dependencies = append(c.dependencies, tc)
+		}
+	}
+	if c.dryRun {
+		// licenses weren't fetched, so there's nothing to report or check
+		return nil
+	}
+	if err := c.notifyWebhook(); err != nil {
+		c.log().Warn("Could not notify webhook", "url", c.webhookURL, "error", err)
+	}
+	if err := c.warnCopyleft(); err != nil {
+		return err
+	}
+	return c.checkUnknown()
+}
+
+// ToolchainVersion returns toolchain declared in go.mod (e.g. go1.21.3), or empty string if go.mod
+// doesn't declare one
+func (c *Client) ToolchainVersion() (string, error) {
+	return mod.ToolchainVersion(c.path)
+}
+
+// ModuleInfo returns module path, Go version and license of the scanned module from its go.mod, see
+// mod.ModuleInfo for how license is declared
+func (c *Client) ModuleInfo() (modulePath, goVersion, license string, err error) {
+	return mod.ModuleInfo(c.path)
+}
+
+// toolchainDependency returns Go toolchain declared in go.mod as pseudo-dependency, or nil if go.mod
+// doesn't declare one. Go is licensed under BSD-3-Clause, so its license isn't fetched.
+func (c *Client) toolchainDependency() (*Repository, error) {
+	v, err := c.ToolchainVersion()
+	if errors.Is(err, os.ErrNotExist) {
+		// workspaces can be parsed without go.mod in their root
+		return nil, nil
+	}
+	if err != nil || v == "" {
+		return nil, err
+	}
+
+	r := &Repository{
+		Name:        toolchainModule,
+		URL:         "https://go.googlesource.com/go",
+		Version:     v,
+		License:     "BSD-3-Clause",
+		LicenseSPDX: "BSD-3-Clause",
+		LicenseURL:  "https://go.dev/LICENSE",
+		IsStdlib:    true,
+		Shortname:   "BSD-3-Clause",
+	}
+	r.PURL = buildPURL(r)
+	classifyLicense(r)
+	return r, nil
+}
+
+// Errors returns errors of dependencies whose license couldn't be fetched while parsing dependencies.
+// Such dependencies are still listed, with empty license fields.
+func (c *Client) Errors() []error {
+	return c.errors
+}
+
+// Reset clears dependencies and errors of the last parsing, so the client can be reused for parsing
+// a modified go.mod with the same configuration
+func (c *Client) Reset() {
+	c.dependencies = nil
+	c.errors = nil
+	c.latencies = nil
+}
+
+// OutdatedDependencies returns dependencies with newer version available on pkg.go.dev, see
+// Repository.LatestVersion. Only dependencies whose licenses are scraped from pkg.go.dev are checked.
+func (c *Client) OutdatedDependencies() []*Repository {
+	var outdated []*Repository
+	for _, d := range c.dependencies {
+		if d.IsOutdated {
+			outdated = append(outdated, d)
+		}
+	}
+	return outdated
+}
+
+// resolveLicenses fetches licenses of repositories that aren't ignored, and returns them.
+// Errors and latencies of the previous run are cleared, see addLicenses.
+func (c *Client) resolveLicenses(ctx context.Context, repos []*Repository, keys map[string]string, thanks bool) []*Repository {
+	c.errors = nil
+	c.latencies = map[string]time.Duration{}
+	return c.addLicenses(ctx, repos, keys, thanks)
+}
+
+// addLicenses fetches licenses of repositories that aren't ignored, and returns them. Errors of
+// dependencies whose license couldn't be fetched are added to c.errors, and latencies to c.latencies.
+// Requests are cancelled once ctx is done.
+func (c *Client) addLicenses(ctx context.Context, repos []*Repository, keys map[string]string, thanks bool) []*Repository {
+	logger := c.log()
+	if c.latencies == nil {
+		c.latencies = map[string]time.Duration{}
+	}
+	repos = c.dedupe(c.filterDependencies(repos))
+	logger.Info("Found dependencies", "count", len(repos))
+
+	gitCl := newGitClient(ctx, keys, thanks, withLogger(logger), withHTTPClient(c.httpClient), withTimeout(c.timeout), withRetry(c.retry), withGitHubBaseURL(c.gitHubURL()), withGitLabHosts(c.gitlabHosts), withActivity(c.abandoned), withLicenseFiles(c.allLicenseFiles), withTaggedLicenses(c.taggedLicenses))
+	concurrency := c.concurrency
+	if concurrency < 1 {
+		concurrency = defaultConcurrency
+	}
+	vendorDir := c.vendorPath()
+	var replaced map[string]string
+	if vendorDir != "" {
+		replaced = c.vendorReplacedPaths()
+	}
+	bar := c.progressBar(len(repos))
+	breakers := newHostBreakers(c.breakerThreshold, c.breakerOpen)
+
+	var pending []*Repository
 	for _, r := range repos {
-		log.Printf("Fetching license for: %s", r.URL)
+		if vendorDir != "" && vendorLicense(vendorDir, r, replaced) || c.cache != nil && c.cache.get(r) {
+			bar.Add(1)
+			continue
+		}
+		pending = append(pending, r)
+	}
+	if c.graphQL {
+		pending = c.fetchGitHubBatches(ctx, gitCl, pending, func(r *Repository) {
+			c.cachePut(c.cachePath(r), r)
+			bar.Add(1)
+		})
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for _, r := range pending {
+		logger.Info("Fetching license", "dependency", r.Name, "version", r.Version, "url", r.URL)
 		wg.Add(1)
 		sem <- struct{}{} // 获取一个信号量
 		go func(r1 *Repository) {
 			defer wg.Done()
 			defer func() { <-sem }() // 释放一个信号量
+			defer bar.Add(1)
+			cachePath := c.cachePath(r1)
+			host := c.fetchHost(r1)
+			breaker := breakers.get(host)
+			if breaker != nil && !breaker.Allow() {
+				logger.Warn("Skipping dependency of unreachable host", "dependency", r1.Name, "host", host)
+				r1.License, r1.Shortname = unreachableLicense, unreachableLicense
+				mu.Lock()
+				c.errors = append(c.errors, fmt.Errorf("fetching %s: %w", r1.Name, ErrHostUnreachable))
+				mu.Unlock()
+				return
+			}
+			start := time.Now()
 			err1 := gitCl.GetLicense(ctx, r1)
+			latency := time.Since(start)
+			if breaker != nil {
+				// host answering e.g. 404 for repository without license is still reachable
+				if isTransientError(err1) {
+					breaker.RecordFailure()
+				} else {
+					breaker.RecordSuccess()
+				}
+			}
+			mu.Lock()
+			c.latencies[r1.Name] = latency
+			mu.Unlock()
 			if err1 != nil {
-				log.Println(err1)
+				logger.Error("Could not fetch license", "dependency", r1.Name, "version", r1.Version, "error", err1)
+				mu.Lock()
+				c.errors = append(c.errors, fmt.Errorf("fetching %s: %w", r1.Name, err1))
+				mu.Unlock()
+				return
 			}
+			c.cachePut(cachePath, r1)
 		}(r)
 	}
 	wg.Wait()
-	c.dependencies = repos
-	return nil
+	bar.Finish()
+	for _, r := range repos {
+		setLicenseHash(r)
+	}
+	c.applyDualLicenses(repos)
+	c.applyLicenseOverrides(repos)
+	return repos
+}
+
+// cachePath returns path of r in cache, or empty string if caching is disabled. It has to be
+// computed before fetching, as fetching can change repository version.
+func (c *Client) cachePath(r *Repository) string {
+	if c.cache == nil {
+		return ""
+	}
+	return c.cache.path(r)
+}
+
+// cachePut stores fetched r in cache at p, if caching is enabled
+func (c *Client) cachePut(p string, r *Repository) {
+	if c.cache == nil {
+		return
+	}
+	if err := c.cache.put(p, r); err != nil {
+		c.log().Warn("Could not cache license", "dependency", r.Name, "version", r.Version, "error", err)
+	}
+}
+
+// progressBar returns progress bar of license fetching written to stderr, so it doesn't mix with
+// printed dependencies. It's hidden unless progress is enabled.
+func (c *Client) progressBar(total int) *progressbar.ProgressBar {
+	return progressbar.NewOptions(total,
+		progressbar.OptionSetWriter(os.Stderr),
+		progressbar.OptionSetVisibility(c.progress),
+		progressbar.OptionSetDescription("Fetching licenses"),
+		progressbar.OptionShowCount(),
+		progressbar.OptionClearOnFinish(),
+	)
+}
+
+// applyLicenseOverrides replaces fetched licenses with the ones from LicenseOverrides
+func (c *Client) applyLicenseOverrides(repos []*Repository) {
+	for _, r := range repos {
+		l, ok := c.LicenseOverrides[r.Name]
+		if !ok {
+			continue
+		}
+		c.log().Warn("Overriding license", "dependency", r.Name, "version", r.Version, "license", l, "fetched_license", r.License)
+		r.License = l
+		r.Shortname = l
+		r.LicenseSPDX = ""
+		if spdxLicenseID.MatchString(l) {
+			r.LicenseSPDX = l
+		}
+		classifyLicense(r)
+	}
+}
+
+// filterDependencies removes ignored repositories and, with WithExcludeStdlib, the ones that are part of Go project
+func (c *Client) filterDependencies(repos []*Repository) []*Repository {
+	repos = c.filterIgnored(repos)
+	if c.excludeStdlib {
+		repos = withoutStdlib(repos)
+	}
+	return repos
+}
+
+// dedupe keeps only the highest version of dependencies with the same import path, which can be
+// required more than once e.g. when multiple modules are replaced by the same fork
+func (c *Client) dedupe(repos []*Repository) []*Repository {
+	deduped, dropped := highestVersions(repos)
+	if len(dropped) > 0 {
+		c.log().Warn("Dropped duplicate dependencies in favor of higher versions", "dependency", strings.Join(dropped, ", "))
+	}
+	return deduped
+}
+
+// highestVersions keeps only the highest version of repositories with the same name, returning
+// name@version of dropped ones
+func highestVersions(repos []*Repository) (deduped []*Repository, dropped []string) {
+	highest := map[string]*Repository{}
+	for _, r := range repos {
+		if h, ok := highest[r.Name]; !ok || semver.Compare(r.Version, h.Version) > 0 {
+			highest[r.Name] = r
+		}
+	}
+	if len(highest) == len(repos) {
+		return repos, nil
+	}
+
+	deduped = make([]*Repository, 0, len(highest))
+	for _, r := range repos {
+		if highest[r.Name] == r {
+			deduped = append(deduped, r)
+		} else {
+			dropped = append(dropped, r.Name+"@"+r.Version)
+		}
+	}
+	return deduped, dropped
+}
+
+// filterIgnored removes repositories matching any of the ignore patterns
+func (c *Client) filterIgnored(repos []*Repository) []*Repository {
+	if len(c.ignore) < 1 {
+		return repos
+	}
+
+	var filtered []*Repository
+	for _, r := range repos {
+		if matchAny(c.ignore, r.Name) {
+			c.log().Info("Ignoring dependency", "dependency", r.Name, "version", r.Version)
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+	return filtered
+}
+
+// matchAny reports whether name equals or matches any of path.Match patterns
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if p == name {
+			return true
+		}
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// gitHubURL returns GitHub Enterprise Server API URL set with WithGitHubBaseURL or GITHUB_API_URL
+// env variable, or empty string for api.github.com
+func (c *Client) gitHubURL() string {
+	u := c.githubBaseURL
+	if u == "" {
+		u = os.Getenv("GITHUB_API_URL")
+	}
+	// GitHub Actions sets GITHUB_API_URL to api.github.com for public GitHub
+	if strings.TrimSuffix(u, "/") == "https://api.github.com" {
+		return ""
+	}
+	return u
+}
+
+// gitKeys returns API keys per host, read from environment variables unless set with WithAPIKey
+func (c *Client) gitKeys() map[string]string {
+	keys := map[string]string{
+		"github.com": os.Getenv("GITHUB_API_KEY"),
+		"gitlab.com": os.Getenv("GITLAB_API_KEY"),
+		// gitea key is used by all Gitea and Forgejo hosts, unless host has its own key
+		"gitea": os.Getenv("GITEA_API_KEY"),
+	}
+	if bbUser := os.Getenv("BITBUCKET_USERNAME"); bbUser != "" {
+		keys["bitbucket.org"] = bbUser + ":" + os.Getenv("BITBUCKET_APP_PASSWORD")
+	}
+	for host, key := range c.apiKeys {
+		keys[host] = key
+	}
+	return keys
+}
+
+// useColor reports whether output written to w should be colored. Unless set with WithColor,
+// colors are disabled when NO_COLOR is set (https://no-color.org/) or w isn't a terminal.
+func (c *Client) useColor(w io.Writer) bool {
+	if c.color != nil {
+		return *c.color
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// isTerminal reports whether w is a file connected to terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+func (c *Client) log() *slog.Logger {
+	if c.logger == nil {
+		return slog.Default()
+	}
+	return c.logger
 }
 
 var (
 	headerRow = []string{"Dependency", "RepoURL", "License", "Version"}
 )
 
+// Print writes dependencies to writeTo in the configured format.
+//
+// Deprecated: use PrintContext, which can be cancelled.
 func (c *Client) Print(writeTo io.Writer) error {
+	return c.PrintContext(context.Background(), writeTo)
+}
+
+// PrintContext writes dependencies to writeTo in the configured format. Row based formats
+// (table, csv, ndjson and markdown) check ctx between rows and return its error once it's done.
+func (c *Client) PrintContext(ctx context.Context, writeTo io.Writer) error {
 	if len(c.dependencies) < 1 {
 		return nil
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.sortDependencies()
 
 	switch c.format {
 	case "table":
-		tw := tablewriter.NewWriter(writeTo)
-		tw.SetHeader(headerRow)
-		for _, d := range c.dependencies {
-			tw.Append([]string{d.Name, color.BlueString(d.URL), d.Shortname, d.Version})
+		render := c.printTable
+		if c.pageSize > 0 && isTerminal(writeTo) {
+			render = func(ctx context.Context, w io.Writer) error { return c.printTablePages(ctx, w, os.Stdin) }
 		}
-		tw.Render()
-	case "json":
-		return json.NewEncoder(writeTo).Encode(c.dependencies)
-	case "csv":
-		csvW := csv.NewWriter(writeTo)
-		defer csvW.Flush()
-		err := csvW.Write(headerRow)
-		if err != nil {
+		if err := render(ctx, writeTo); err != nil {
 			return err
 		}
+		if c.summary {
+			c.printSummaryTable(writeTo)
+		}
+		return nil
+	case "json":
+		enc := json.NewEncoder(writeTo)
+		enc.SetIndent(c.jsonPrefix, c.jsonIndent)
+		if !c.legacyJSON {
+			return enc.Encode(struct {
+				Modules []*Repository  `json:"modules"`
+				Summary map[string]int `json:"summary"`
+			}{c.dependencies, c.spdxSummary()})
+		}
+		if c.summary {
+			return enc.Encode(struct {
+				Dependencies []*Repository  `json:"dependencies"`
+				Summary      map[string]int `json:"summary"`
+			}{c.dependencies, c.Summary()})
+		}
+		return enc.Encode(c.dependencies)
+	case "ndjson":
+		enc := json.NewEncoder(writeTo)
 		for _, d := range c.dependencies {
-			err = csvW.Write([]string{d.Project, d.URL, d.License})
-			if err != nil {
+			if err := ctx.Err(); err != nil {
 				return err
 			}
+			if err := enc.Encode(d); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		return c.printCSV(ctx, writeTo)
+	case "spdx-json":
+		return c.printSPDXJSON(writeTo)
+	case "spdx-tv":
+		return c.printSPDXTagValue(writeTo)
+	case "html":
+		return c.printHTML(writeTo)
+	case "markdown":
+		return c.printMarkdown(ctx, writeTo)
+	case "yaml":
+		enc := yaml.NewEncoder(writeTo)
+		if err := enc.Encode(c.dependencies); err != nil {
+			return err
 		}
-		return csvW.Error()
+		return enc.Close()
+	case "template":
+		return c.printTemplate(writeTo)
+	case "xml":
+		return c.printXML(writeTo)
+	case "junit":
+		return c.printJUnit(writeTo)
+	case "cyclonedx-json":
+		return c.printCycloneDXJSON(writeTo)
+	case "cyclonedx-xml":
+		return c.printCycloneDXXML(writeTo)
+	case "sarif":
+		return c.printSARIF(writeTo)
+	case "dot":
+		return c.printDOT(writeTo)
+	case "benchmark":
+		c.printBenchmark(writeTo)
+		return nil
+	case "fossa":
+		return c.printFOSSA(writeTo)
+	case "compat":
+		c.printCompat(writeTo)
+		return nil
 	}
 
-	// shouldn't be possible to get this error
-	return fmt.Errorf("invalid output provided (%s) - allowed ones are [stdout, json, csv]", c.output)
+	// shouldn't be possible to get this error, as NewClient validates format
+	return fmt.Errorf("invalid format provided (%s) - allowed ones are [%s]", c.format, strings.Join(keys(validFormats), ", "))
+}
+
+func keys[V any](m map[string]V) []string {
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return ks
 }
 
 func Print(path string, indirect bool, writeTo io.Writer) error {
 	return PrintTo(path, "table", "stdout", indirect, writeTo)
 }
 
-func PrintTo(path, format, output string, indirect bool, writeTo io.Writer) error {
-	c, err := NewClient(path, format, output)
+// Write prints dependencies to all outputs set with WithOutputs at once, and to writer set with
+// WithOutputWriter. Unless set with WithOutputFile, file output is written to dependencies.<extension>
+// and both output to glice-output.<extension> in the working directory.
+func (c *Client) Write() error {
+	var writers []io.Writer
+	var files []*os.File
+	for _, o := range c.outputs {
+		if o == "stdout" || o == "both" {
+			writers = append(writers, os.Stdout)
+		}
+		if o == "file" || o == "both" {
+			f, err := os.Create(c.outputFileName(o))
+			if err != nil {
+				for _, f := range files {
+					f.Close()
+				}
+				return err
+			}
+			files = append(files, f)
+			writers = append(writers, f)
+		}
+	}
+	if c.outputWriter != nil {
+		writers = append(writers, c.outputWriter)
+	}
+
+	// stdout is passed as is when it's the only output, so that table can be paged in terminal
+	w := io.MultiWriter(writers...)
+	if len(writers) == 1 {
+		w = writers[0]
+	}
+	err := c.PrintContext(context.Background(), w)
+	for _, f := range files {
+		if err1 := f.Close(); err1 != nil && err == nil {
+			err = err1
+		}
+	}
+	return err
+}
+
+// outputFileName returns name of the file written with file or both output
+func (c *Client) outputFileName(output string) string {
+	if c.outputFile != "" {
+		return c.outputFile
+	}
+	if output == "both" {
+		return fmt.Sprintf("glice-output.%s", formatExtensions[c.format])
+	}
+	return fmt.Sprintf("dependencies.%s", formatExtensions[c.format])
+}
+
+// PrintTo prints dependencies of path in the given format to writeTo. If writeTo is nil,
+// dependencies are written to comma separated outputs instead (e.g. "stdout,file").
+// With "both" output, dependencies are written to glice-output.<extension> file as well.
+// If check modes are provided, their license checks are run after printing and
+// violations are returned as an error.
+func PrintTo(path, format, output string, indirect bool, writeTo io.Writer, modes ...CheckMode) error {
+	c, err := NewClient(path, WithFormat(format), WithOutputs(strings.Split(output, ",")...))
 	if err != nil {
 		return err
 	}
@@ -152,29 +927,141 @@ func PrintTo(path, format, output string, indirect bool, writeTo io.Writer) erro
 		return err
 	}
 
-	c.Print(writeTo)
+	if writeTo == nil {
+		if err = c.Write(); err != nil {
+			return err
+		}
+	} else {
+		if err = c.printTee(writeTo); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range modes {
+		if err = c.Check(m); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// printTee prints to writeTo, and to output file as well when both output is set
+func (c *Client) printTee(writeTo io.Writer) error {
+	if c.output != "both" {
+		return c.PrintContext(context.Background(), writeTo)
+	}
+
+	f, err := os.Create(c.outputFileName(c.output))
+	if err != nil {
+		return err
+	}
+	err = c.PrintContext(context.Background(), io.MultiWriter(writeTo, f))
+	if err1 := f.Close(); err1 != nil && err == nil {
+		err = err1
+	}
+	return err
+}
+
 func ListRepositories(path string, withIndirect bool) ([]*Repository, error) {
 	modules, err := mod.Parse(path, withIndirect)
 	if err != nil {
 		return nil, err
 	}
+	return toRepositories(modules, newRepoCache(), forgeHosts{}), nil
+}
 
-	repos := make([]*Repository, len(modules))
-	for i, mod := range modules {
-		repos[i] = getRepository(mod)
+// ListAllRepositories lists repositories of all modules in directory tree rooted at root, see
+// mod.ParseAll. Repositories required by several modules are listed once, with the highest version,
+// and modules found in the tree aren't listed as dependencies of each other.
+func ListAllRepositories(root string, withIndirect bool) ([]*Repository, error) {
+	modules, err := mod.ParseAll(root, withIndirect)
+	if err != nil {
+		return nil, err
 	}
 
+	repos, _ := highestVersions(toRepositories(modules, newRepoCache(), forgeHosts{}))
 	return repos, nil
+}
+
+// ListRepositoriesResult holds repositories listed by ListRepositoriesVerbose and errors of
+// dependencies that were skipped
+type ListRepositoriesResult struct {
+	Repositories []*Repository
+	Errors       []error
+}
+
+// ListRepositoriesVerbose is like ListRepositories, but dependencies with malformed module path or
+// version are skipped and reported in Errors, instead of making the whole go.mod fail later. When
+// go.mod can't be read or parsed, Errors holds just that error.
+func ListRepositoriesVerbose(path string, withIndirect bool) ListRepositoriesResult {
+	modules, err := mod.Parse(path, withIndirect)
+	if err != nil {
+		return ListRepositoriesResult{Errors: []error{err}}
+	}
+	return listRepositories(modules, newRepoCache(), forgeHosts{})
+}
+
+// listRepositories converts valid modules to repositories, modules with malformed path or version
+// are reported in Errors
+func listRepositories(modules []module.Version, rc *repoCache, hosts forgeHosts) ListRepositoriesResult {
+	var res ListRepositoriesResult
+	valid := make([]module.Version, 0, len(modules))
+	for _, m := range modules {
+		if err := module.Check(m.Path, m.Version); err != nil {
+			res.Errors = append(res.Errors, err)
+			continue
+		}
+		valid = append(valid, m)
+	}
+	res.Repositories = toRepositories(valid, rc, hosts)
+	return res
+}
+
+// listRepositories returns repositories of modules, logging the skipped ones
+func (c *Client) listRepositories(modules []module.Version) []*Repository {
+	res := listRepositories(modules, c.repoCache, c.forgeHosts())
+	for _, err := range res.Errors {
+		c.log().Warn("Skipping dependency", "error", err)
+	}
+	return res.Repositories
+}
 
+// forgeHosts are hostnames of self-hosted forges, whose repositories are fetched using their API
+type forgeHosts struct {
+	gitlab []string
+	// gitea are Gitea or Forgejo hosts
+	gitea []string
 }
 
-func getRepository(mod module.Version) *Repository {
-	return getOtherRepo(mod)
+func (c *Client) forgeHosts() forgeHosts {
+	return forgeHosts{gitlab: c.gitlabHosts, gitea: c.giteaHosts}
+}
+
+// toRepositories converts modules to repositories, modules hosted on hosts are treated as
+// GitLab or Gitea projects
+func toRepositories(modules []module.Version, rc *repoCache, hosts forgeHosts) []*Repository {
+	repos := make([]*Repository, len(modules))
+	for i, mod := range modules {
+		repos[i] = getRepository(mod, rc, hosts)
+	}
+	return repos
+}
+
+// getRepository returns repository of module mod with its package URL
+func getRepository(mod module.Version, rc *repoCache, hosts forgeHosts) *Repository {
+	r := resolveRepository(mod, rc, hosts)
+	r.PURL = buildPURL(r)
+	r.IsStdlib = isStdlib(mod.Path)
+	return r
+}
+
+func resolveRepository(mod module.Version, rc *repoCache, hosts forgeHosts) *Repository {
 	s := mod.Path
 	spl := strings.Split(s, "/")
+	// paths without domain (e.g. standard library) can't be resolved with go-import or GOPROXY
+	if !strings.Contains(spl[0], ".") {
+		return &Repository{Name: s, Version: mod.Version}
+	}
 	switch spl[0] {
 	case "github.com", "gitlab.com", "bitbucket.org":
 		if len(spl) < 3 {
@@ -183,69 +1070,347 @@ func getRepository(mod module.Version) *Repository {
 		return &Repository{URL: "https://" + spl[0] + "/" + spl[1] + "/" + spl[2], Host: spl[0], Author: spl[1], Project: spl[2], Name: s, Version: mod.Version}
 
 	case "gopkg.in":
-		if len(spl) < 3 {
-			return &Repository{Name: s}
+		author, project, ok := gopkgInRepo(spl)
+		if !ok {
+			r := repoFromRedirect(goGetClient, "https://"+s, rc.log())
+			if r == nil {
+				return &Repository{Name: s, Version: mod.Version}
+			}
+			r.Name, r.Version = s, mod.Version
+			return r
 		}
-		return &Repository{URL: "https://github.com/" + spl[1] + "/" + strings.Split(spl[2], ".")[0], Host: "github.com", Author: spl[1], Project: strings.Split(spl[2], ".")[0], Name: s, Version: mod.Version}
+		return &Repository{URL: "https://github.com/" + author + "/" + project, Host: "github.com", Author: author, Project: project, Name: s, Version: mod.Version}
+	}
+	if containsFold(hosts.gitlab, spl[0]) && len(spl) >= 3 {
+		return &Repository{URL: "https://" + spl[0] + "/" + spl[1] + "/" + spl[2], Host: spl[0], Author: spl[1], Project: spl[2], Name: s, Version: mod.Version}
+	}
+	if containsFold(hosts.gitea, spl[0]) && len(spl) >= 3 {
+		return &Repository{URL: "https://" + spl[0] + "/" + spl[1] + "/" + spl[2], Host: "gitea", HostURL: spl[0], Author: spl[1], Project: spl[2], Name: s, Version: mod.Version}
+	}
+	return getOtherRepo(mod, rc)
+}
+
+// goImport is content of go-import meta tag: "import-prefix vcs repo-root"
+type goImport struct {
+	Prefix   string
+	VCS      string
+	RepoRoot string
+}
+
+// repoCache caches repositories resolved by getOtherRepo, so that each module version is resolved
+// only once. It's safe for concurrent use, nil repoCache doesn't cache anything.
+type repoCache struct {
+	mu    sync.RWMutex
+	repos map[string]*Repository
+	// offline repoCache doesn't resolve import paths over network, modules are linked to pkg.go.dev
+	offline bool
+	// logger logs import paths that couldn't be resolved, slog.Default() is used when nil
+	logger *slog.Logger
+}
+
+func newRepoCache() *repoCache {
+	return &repoCache{repos: map[string]*Repository{}}
+}
+
+func (rc *repoCache) log() *slog.Logger {
+	if rc == nil || rc.logger == nil {
+		return slog.Default()
+	}
+	return rc.logger
+}
+
+func (rc *repoCache) get(key string) (*Repository, bool) {
+	if rc == nil {
+		return nil, false
+	}
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	r, ok := rc.repos[key]
+	return r, ok
+}
+
+// store caches r unless other repository was cached under key meanwhile, returning the cached one
+func (rc *repoCache) store(key string, r *Repository) *Repository {
+	if rc == nil {
+		return r
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if v, ok := rc.repos[key]; ok {
+		return v
+	}
+	rc.repos[key] = r
+	return r
+}
+
+// gopkgInRepo returns GitHub repository of gopkg.in import path split by slashes. gopkg.in/pkg.v3
+// is github.com/go-pkg/pkg, while gopkg.in/user/pkg.v3 is github.com/user/pkg.
+func gopkgInRepo(spl []string) (author, project string, ok bool) {
+	if len(spl) < 2 {
+		return "", "", false
+	}
+	if pkg, ok := gopkgInPackage(spl[1]); ok {
+		return "go-" + pkg, pkg, true
+	}
+	if len(spl) < 3 {
+		return "", "", false
+	}
+	if pkg, ok := gopkgInPackage(spl[2]); ok {
+		return spl[1], pkg, true
+	}
+	return spl[1], strings.Split(spl[2], ".")[0], true
+}
+
+// gopkgInPackage returns package name of gopkg.in path element with version selector, e.g. yaml
+// of yaml.v3 or yaml.v3-unstable
+func gopkgInPackage(elem string) (string, bool) {
+	i := strings.LastIndex(elem, ".v")
+	if i < 1 {
+		return "", false
+	}
+	major := strings.TrimSuffix(elem[i+2:], "-unstable")
+	if major == "" || strings.Trim(major, "0123456789") != "" {
+		return "", false
 	}
-	return getOtherRepo(mod)
+	return elem[:i], true
 }
 
-type metaData struct {
-	Import string `meta:"go-import"`
-	Source string `meta:"go-source"`
+// repoFromRedirect returns repository rawURL redirects to, or nil if it doesn't redirect to another host
+func repoFromRedirect(hc *http.Client, rawURL string, logger *slog.Logger) *Repository {
+	resp, err := hc.Get(rawURL)
+	if err != nil {
+		logger.Warn("Could not resolve redirect", "url", rawURL, "error", err)
+		return nil
+	}
+	resp.Body.Close()
+	final := resp.Request.URL
+	if resp.StatusCode != http.StatusOK || final.Host == "" || strings.EqualFold(final.Host, "gopkg.in") {
+		return nil
+	}
+	return repoFromRoot("https://" + final.Host + final.Path)
 }
 
-var cache = map[string]*Repository{}
+// goGetClient is used to fetch go-import meta tags of vanity import paths
+var goGetClient = &http.Client{Timeout: defaultTimeout}
 
 // Resolve indirect repos as described here:
 // https://golang.org/cmd/go/#hdr-Remote_import_paths
-func getOtherRepo(mod module.Version) *Repository {
+func getOtherRepo(mod module.Version, rc *repoCache) *Repository {
 	name := mod.Path
-	if v, ok := cache[name]; ok {
+	if v, ok := rc.get(mod.String()); ok {
 		return v
 	}
 
 	lcs := &Repository{Name: name, Version: mod.Version}
 	lcs.URL = fmt.Sprintf("https://pkg.go.dev/%s", name)
 	lcs.Host = "pkg.go.dev"
+	if rc != nil && rc.offline {
+		return rc.store(mod.String(), lcs)
+	}
+
+	if imp, err := fetchGoImport(goGetClient, "https://"+name+"?go-get=1", name); err != nil {
+		rc.log().Warn("Could not resolve go-import, trying GOPROXY", "dependency", name, "error", err)
+		if r := repoFromProxy(mod, rc.log()); r != nil {
+			lcs = r
+		}
+	} else if r := repoFromRoot(imp.RepoRoot); r != nil {
+		r.Name, r.Version = name, mod.Version
+		lcs = r
+	}
+
+	return rc.store(mod.String(), lcs)
+}
 
-	cache[name] = lcs
-	return lcs
+// fetchGoImport fetches rawURL and returns go-import meta tag matching importPath
+func fetchGoImport(hc *http.Client, rawURL, importPath string) (*goImport, error) {
+	resp, err := hc.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return parseGoImport(resp.Body, importPath)
 }
 
+// parseGoImport parses go-import meta tags from html the same way go command does, returning
+// the one whose prefix matches importPath
+func parseGoImport(r io.Reader, importPath string) (*goImport, error) {
+	d := xml.NewDecoder(r)
+	d.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		if strings.EqualFold(charset, "ascii") || strings.EqualFold(charset, "utf-8") {
+			return input, nil
+		}
+		return nil, fmt.Errorf("can't decode XML document using charset %q", charset)
+	}
+	d.Strict = false
+	for {
+		t, err := d.RawToken()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		if e, ok := t.(xml.EndElement); ok && strings.EqualFold(e.Name.Local, "head") {
+			break
+		}
+		e, ok := t.(xml.StartElement)
+		if !ok || !strings.EqualFold(e.Name.Local, "meta") || xmlAttr(e, "name") != "go-import" {
+			continue
+		}
+		f := strings.Fields(xmlAttr(e, "content"))
+		if len(f) != 3 {
+			continue
+		}
+		if importPath == f[0] || strings.HasPrefix(importPath, f[0]+"/") {
+			return &goImport{Prefix: f[0], VCS: f[1], RepoRoot: f[2]}, nil
+		}
+	}
+	return nil, fmt.Errorf("no go-import meta tag found for %s", importPath)
+}
+
+func xmlAttr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if strings.EqualFold(a.Name.Local, name) {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// repoFromProxy resolves repository of m from origin reported by module proxies in GOPROXY,
+// which also see private modules that aren't publicly accessible. It returns nil if proxies
+// don't know the module or don't report its origin, falling back to pkg.go.dev.
+func repoFromProxy(m module.Version, logger *slog.Logger) *Repository {
+	info, err := mod.ProxyInfo(m.Path, m.Version)
+	if err != nil {
+		logger.Warn("Could not resolve via GOPROXY, falling back to pkg.go.dev", "dependency", m.Path, "error", err)
+		return nil
+	}
+	if info.Origin == nil || info.Origin.URL == "" {
+		return nil
+	}
+	r := repoFromRoot(info.Origin.URL)
+	if r == nil {
+		return nil
+	}
+	r.Name, r.Version = m.Path, info.Version
+	return r
+}
+
+// repoFromRoot returns repository with URL, Host, Author and Project set from go-import repo root
+func repoFromRoot(root string) *Repository {
+	u, err := url.Parse(root)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	p := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	r := &Repository{URL: "https://" + u.Host + "/" + p, Host: u.Host}
+	if spl := strings.Split(p, "/"); len(spl) >= 2 {
+		r.Author, r.Project = spl[len(spl)-2], spl[len(spl)-1]
+	} else {
+		r.Project = p
+	}
+	return r
+}
+
+// defaultLicenseFileTemplate is name of license files written by WriteLicensesToFile
+var defaultLicenseFileTemplate = template.Must(template.New("license-file").Parse("{{.Author}}-{{.Project}}-license.MD"))
+
+// WriteLicensesToFile writes license text of each dependency into a separate file in directory set
+// with WithLicenseOutputDir, named by template set with WithLicenseFileTemplate.
+// Dependencies without license text are skipped.
 func (c *Client) WriteLicensesToFile() error {
+	_, _, err := c.writeLicenseFiles()
+	return err
+}
+
+// checksumsFileName is name of file WriteLicensesWithHashes writes checksums of license files to
+const checksumsFileName = "CHECKSUMS.txt"
+
+// WriteLicensesWithHashes writes license files like WriteLicensesToFile, along with CHECKSUMS.txt
+// listing SHA-256 digest of each of them in sha256sum format, so `sha256sum -c CHECKSUMS.txt` run in
+// the directory tells which license texts changed since they were written.
+func (c *Client) WriteLicensesWithHashes() error {
+	dir, names, err := c.writeLicenseFiles()
+	if err != nil || len(names) < 1 {
+		return err
+	}
+
+	var sb strings.Builder
+	for _, d := range c.dependencies {
+		if name, ok := names[d]; ok {
+			fmt.Fprintf(&sb, "%s  %s\n", d.LicenseHash, name)
+		}
+	}
+	return os.WriteFile(filepath.Join(dir, checksumsFileName), []byte(sb.String()), 0644)
+}
+
+// writeLicenseFiles writes license files of dependencies, returning directory they were written to
+// and names of the files per dependency. LicenseHash of written dependencies is set from the text.
+func (c *Client) writeLicenseFiles() (string, map[*Repository]string, error) {
 	if len(c.dependencies) < 1 {
-		return nil
+		return "", nil, nil
+	}
+	dir := c.licenseDir
+	if dir == "" {
+		dir = filepath.Join(c.path, "licenses")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", nil, err
 	}
-	os.Mkdir("licenses", 0777)
 
+	names := map[*Repository]string{}
 	for _, d := range c.dependencies {
 		if d.Text == "" {
 			continue
 		}
 
-		dec, err := base64.StdEncoding.DecodeString(d.Text)
+		text, err := d.LicenseText()
 		if err != nil {
-			return err
+			return "", nil, err
 		}
 
-		f, err := os.Create(filepath.Join(c.path, "licenses", d.Author+"-"+d.Project+"-license.MD"))
+		name, err := c.licenseFileName(d)
 		if err != nil {
-			return err
+			return "", nil, err
+		}
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			return "", nil, err
 		}
 
-		if _, err := f.Write(dec); err != nil {
-			return err
+		if _, err := f.WriteString(text); err != nil {
+			return "", nil, err
 		}
 		if err := f.Sync(); err != nil {
-			return err
+			return "", nil, err
 		}
 
 		if err := f.Close(); err != nil {
-			return err
+			return "", nil, err
 		}
+		setLicenseHash(d)
+		names[d] = name
 	}
 
-	return nil
+	return dir, names, nil
+}
+
+// licenseFileName returns name of file license of d is written to
+func (c *Client) licenseFileName(d *Repository) (string, error) {
+	t := c.licenseFileTmpl
+	if t == nil {
+		t = defaultLicenseFileTemplate
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, d); err != nil {
+		return "", fmt.Errorf("could not name license file of %s: %w", d.Name, err)
+	}
+	if sb.Len() == 0 {
+		return "", fmt.Errorf("could not name license file of %s: template produced empty name", d.Name)
+	}
+	return sb.String(), nil
 }
@@ -2,10 +2,23 @@ package glice
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+
+	"golang.org/x/mod/module"
+
+	"github.com/ribice/glice/v2/mod"
 )
 
 func wd() string {
@@ -13,16 +26,146 @@ func wd() string {
 	return d
 }
 
-var gliceDeps = []string{"github.com/fatih/color", "github.com/google/go-github",
-	"github.com/keighl/metabolize", "github.com/olekukonko/tablewriter",
-	"golang.org/x/mod", "golang.org/x/oauth2"}
+var gliceDeps = []string{"github.com/CycloneDX/cyclonedx-go", "github.com/fatih/color", "github.com/fsnotify/fsnotify", "github.com/gocolly/colly",
+	"github.com/google/go-github", "github.com/olekukonko/tablewriter", "github.com/schollz/progressbar/v3",
+	"github.com/shurcooL/githubv4", "github.com/spdx/tools-golang", "github.com/xanzy/go-gitlab", "golang.org/x/mod", "golang.org/x/oauth2",
+	"golang.org/x/term", "gopkg.in/yaml.v3"}
 
 func TestGetOtherRepo(t *testing.T) {
-	if getOtherRepo("golang.org/x/net/context/ctxhttp").URL != "https://go.googlesource.com/net" {
+	if getOtherRepo(module.Version{Path: "golang.org/x/net/context/ctxhttp"}, nil).URL != "https://go.googlesource.com/net" {
 		t.Error("Wrong URL")
 	}
 }
 
+func TestGetOtherRepoConcurrency(t *testing.T) {
+	t.Setenv("GOPROXY", "off")
+	defer func(hc *http.Client) { goGetClient = hc }(goGetClient)
+	goGetClient = NewMockHTTPClient(map[string]string{
+		"https://example.com/kiss?go-get=1": `<html><head><meta name="go-import" content="example.com/kiss git https://github.com/ribice/kiss"></head></html>`,
+	})
+
+	mods := []module.Version{{Path: "example.com/kiss", Version: "v1.0.0"}, {Path: "example.com/missing", Version: "v1.0.0"}}
+	rc := newRepoCache()
+	got := make([][]*Repository, 20)
+	var wg sync.WaitGroup
+	for i := range got {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for _, m := range mods {
+				got[i] = append(got[i], getOtherRepo(m, rc))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got[0][0].URL != "https://github.com/ribice/kiss" || got[0][1].Host != "pkg.go.dev" {
+		t.Fatalf("unexpected repositories %+v, %+v", got[0][0], got[0][1])
+	}
+	for i := range got {
+		for j := range mods {
+			if got[i][j] != got[0][j] {
+				t.Errorf("expected %s to be resolved to the same cached repository", mods[j])
+			}
+		}
+	}
+	if other := getOtherRepo(mods[0], newRepoCache()); other == got[0][0] {
+		t.Error("expected repositories not to be shared between caches")
+	}
+}
+
+func TestParseGoImport(t *testing.T) {
+	page := `<!DOCTYPE html>
+<html>
+<head>
+<meta name="go-import" content="golang.org/x/tools git https://go.googlesource.com/tools">
+<meta name="go-import" content="golang.org/x/net git https://go.googlesource.com/net">
+<meta name="go-source" content="golang.org/x/net https://github.com/golang/net/ https://github.com/golang/net/tree/master{/dir} https://github.com/golang/net/blob/master{/dir}/{file}#L{line}">
+</head>
+<body>
+<meta name="go-import" content="golang.org/x/net/context git https://example.com/wrong">
+</body>
+</html>`
+	tests := map[string]struct {
+		importPath string
+		want       *goImport
+		wantErr    bool
+	}{
+		"exact prefix": {
+			importPath: "golang.org/x/net",
+			want:       &goImport{Prefix: "golang.org/x/net", VCS: "git", RepoRoot: "https://go.googlesource.com/net"},
+		},
+		"subpackage": {
+			importPath: "golang.org/x/net/context/ctxhttp",
+			want:       &goImport{Prefix: "golang.org/x/net", VCS: "git", RepoRoot: "https://go.googlesource.com/net"},
+		},
+		"partial element match": {
+			importPath: "golang.org/x/network",
+			wantErr:    true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseGoImport(strings.NewReader(page), tt.importPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseGoImport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseGoImport() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchGoImport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("go-get") != "1" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `<html><head><meta name="go-import" content="example.com/kiss git https://github.com/ribice/kiss.git"></head></html>`)
+	}))
+	defer srv.Close()
+
+	imp, err := fetchGoImport(srv.Client(), srv.URL+"/kiss?go-get=1", "example.com/kiss")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Repository{URL: "https://github.com/ribice/kiss", Host: "github.com", Author: "ribice", Project: "kiss"}
+	if got := repoFromRoot(imp.RepoRoot); !reflect.DeepEqual(got, want) {
+		t.Errorf("repoFromRoot() = %+v, want %+v", got, want)
+	}
+
+	if _, err := fetchGoImport(srv.Client(), srv.URL+"/kiss", "example.com/kiss"); err == nil {
+		t.Error("expected error for not found page")
+	}
+}
+
+func TestRepoFromProxy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/company.com/kiss/@v/v1.0.0.info":
+			fmt.Fprint(w, `{"Version": "v1.0.0", "Origin": {"VCS": "git", "URL": "https://gitlab.company.com/ribice/kiss.git"}}`)
+		case "/company.com/no-origin/@v/v1.0.0.info":
+			fmt.Fprint(w, `{"Version": "v1.0.0"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("GOPROXY", srv.URL)
+
+	want := &Repository{Name: "company.com/kiss", Version: "v1.0.0", URL: "https://gitlab.company.com/ribice/kiss", Host: "gitlab.company.com", Author: "ribice", Project: "kiss"}
+	if got := repoFromProxy(module.Version{Path: "company.com/kiss", Version: "v1.0.0"}, slog.Default()); !reflect.DeepEqual(got, want) {
+		t.Errorf("repoFromProxy() = %+v, want %+v", got, want)
+	}
+	for _, path := range []string{"company.com/no-origin", "company.com/missing"} {
+		if got := repoFromProxy(module.Version{Path: path, Version: "v1.0.0"}, slog.Default()); got != nil {
+			t.Errorf("repoFromProxy(%s) = %+v, want nil", path, got)
+		}
+	}
+}
+
 func TestClient_ParseDependencies(t *testing.T) {
 	tests := map[string]struct {
 		path            string
@@ -66,6 +209,192 @@ func TestClient_ParseDependencies(t *testing.T) {
 	}
 }
 
+func TestClient_OutdatedDependencies(t *testing.T) {
+	outdated := &Repository{Name: "example.com/old", Version: "v1.0.0", LatestVersion: "v1.2.0", IsOutdated: true}
+	c := &Client{dependencies: []*Repository{
+		outdated,
+		{Name: "example.com/latest", Version: "v1.2.0", LatestVersion: "v1.2.0"},
+		{Name: "github.com/ribice/kiss", Version: "v1.0.0"},
+	}}
+	if got := c.OutdatedDependencies(); !reflect.DeepEqual(got, []*Repository{outdated}) {
+		t.Errorf("OutdatedDependencies() = %+v, want %+v", got, outdated)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	hc := NewMockHTTPClient(map[string]string{
+		"https://api.github.com/repos/ribice/kiss/license": `{"content": "bGljZW5zZS10ZXh0", "license": {"key": "mit", "name": "MIT License"}}`,
+	})
+	c, err := NewClient(wd(), WithHTTPClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	repos := c.resolveLicenses(context.Background(), []*Repository{
+		{Name: "github.com/ribice/kiss", Host: "github.com", Author: "ribice", Project: "kiss"},
+		{Name: "github.com/ribice/missing", Host: "github.com", Author: "ribice", Project: "missing"},
+		{Name: "example.com/missing"},
+	}, map[string]string{}, false)
+	if len(repos) != 3 || repos[0].License != "MIT" || repos[1].License != "" || repos[2].License != "" {
+		t.Fatalf("unexpected repositories %+v, %+v, %+v", repos[0], repos[1], repos[2])
+	}
+
+	errs := c.Errors()
+	if len(errs) != 2 {
+		t.Fatalf("expected two errors, got %v", errs)
+	}
+	// dependencies are fetched concurrently, so errors aren't ordered
+	for _, prefix := range []string{
+		"fetching github.com/ribice/missing: ",
+		"fetching example.com/missing: visit https://pkg.go.dev/example.com/missing: ",
+	} {
+		if !strings.HasPrefix(errs[0].Error(), prefix) && !strings.HasPrefix(errs[1].Error(), prefix) {
+			t.Errorf("expected error starting with %q, got %v", prefix, errs)
+		}
+	}
+
+	c.resolveLicenses(context.Background(), nil, map[string]string{}, false)
+	if errs := c.Errors(); len(errs) != 0 {
+		t.Errorf("expected errors to be reset, got %v", errs)
+	}
+}
+
+func TestClient_IncludeToolchain(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/a\n\ngo 1.21\n\ntoolchain go1.21.3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		opts []Option
+		want []*Repository
+	}{
+		"without option": {},
+		"with option": {
+			opts: []Option{WithIncludeToolchain()},
+			want: []*Repository{{
+				Name: "golang.org/toolchain", URL: "https://go.googlesource.com/go", Version: "go1.21.3", License: "BSD-3-Clause",
+				Shortname: "BSD-3-Clause", LicenseSPDX: "BSD-3-Clause", LicenseURL: "https://go.dev/LICENSE", Category: CategoryPermissive,
+				RiskScore: 2, PURL: "pkg:golang/golang.org/toolchain@go1.21.3", IsStdlib: true,
+			}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewClient(dir, append(tt.opts, WithColor(false))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := c.ParseDependencies(false, false); err != nil {
+				t.Fatal(err)
+			}
+			if (len(c.dependencies) > 0 || len(tt.want) > 0) && !reflect.DeepEqual(c.dependencies, tt.want) {
+				t.Errorf("dependencies = %+v, want %+v", c.dependencies, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_ParseDependenciesDryRun(t *testing.T) {
+	dir := t.TempDir()
+	goMod := "module example.com/a\n\ngo 1.21\n\nrequire (\n\tgithub.com/ribice/kiss v1.0.0\n\texample.com/vanity v1.2.0\n)\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+	t.Setenv("GOPROXY", srv.URL)
+	defer func(hc *http.Client) { goGetClient = hc }(goGetClient)
+	goGetClient = srv.Client()
+
+	c, err := NewClient(dir, WithDryRun(), WithHTTPClient(srv.Client()), WithColor(false), WithFailOnUnknown())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// starring is skipped, so it doesn't need API key
+	if err := c.ParseDependencies(false, true); err != nil {
+		t.Fatal(err)
+	}
+	if requests > 0 {
+		t.Errorf("expected no network calls, got %d", requests)
+	}
+	if len(c.dependencies) != 2 {
+		t.Fatalf("expected 2 dependencies, got %+v", c.dependencies)
+	}
+	for _, d := range c.dependencies {
+		if d.License != "" {
+			t.Errorf("expected license of %s not to be fetched, got %s", d.Name, d.License)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := c.PrintContext(context.Background(), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), dryRunLicense); n != 2 {
+		t.Errorf("expected 2 %s licenses, got %d in:\n%s", dryRunLicense, n, buf.String())
+	}
+}
+
+func TestClient_Reset(t *testing.T) {
+	dir := t.TempDir()
+	write := func(goMod string) {
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("module example.com/a\n\ngo 1.21\n\nrequire example.com/b v1.0.0\n")
+	c, err := NewClient(dir, WithDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseDependencies(false, false); err != nil {
+		t.Fatal(err)
+	}
+	c.errors = []error{errors.New("fetching example.com/b: not found")}
+
+	c.Reset()
+	if c.dependencies != nil || c.Errors() != nil {
+		t.Fatalf("expected no dependencies and errors after Reset, got %+v and %v", c.dependencies, c.errors)
+	}
+
+	write("module example.com/a\n\ngo 1.21\n\nrequire (\n\texample.com/b v1.0.0\n\texample.com/c v1.1.0\n)\n")
+	if err := c.ParseDependencies(false, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.dependencies) != 2 {
+		t.Errorf("expected 2 dependencies after re-parsing, got %+v", c.dependencies)
+	}
+}
+
+func TestClient_ParseDependenciesIgnore(t *testing.T) {
+	c, err := NewClient(wd(), WithIgnore("golang.org/x/*", "github.com/fatih/color"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseDependencies(false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range c.dependencies {
+		if strings.HasPrefix(d.Name, "golang.org/x/") || d.Name == "github.com/fatih/color" {
+			t.Errorf("expected %s to be ignored", d.Name)
+		}
+	}
+	var want int
+	for _, d := range gliceDeps {
+		if !strings.HasPrefix(d, "golang.org/x/") && d != "github.com/fatih/color" {
+			want++
+		}
+	}
+	if len(c.dependencies) != want {
+		t.Errorf("expected %d dependencies, got %d", want, len(c.dependencies))
+	}
+}
+
 func TestPrint(t *testing.T) {
 	tests := map[string]struct {
 		path            string
@@ -117,6 +446,11 @@ func TestPrintTo(t *testing.T) {
 			wantWriteOutput: true,
 			format:          "csv",
 		},
+		"yaml format": {
+			path:            wd(),
+			wantWriteOutput: true,
+			format:          "yaml",
+		},
 		"valid path": {
 			path:            wd(),
 			wantWriteOutput: true,
@@ -153,7 +487,9 @@ func TestClient_Print(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			c := &Client{dependencies: tt.dependencies, format: "table", output: "stdout"}
 			output := &bytes.Buffer{}
-			c.Print(output)
+			if err := c.Print(output); err != nil {
+				t.Errorf("expected no error for table format, got %v", err)
+			}
 			if (output.String() != "") != tt.wantOutput {
 				t.Error("wantOutput and gotOutput do not match")
 			}
@@ -161,6 +497,293 @@ func TestClient_Print(t *testing.T) {
 	}
 }
 
+func TestClient_PrintInvalidFormat(t *testing.T) {
+	c := &Client{format: "pdf", dependencies: []*Repository{{Name: "github.com/ribice/glice"}}}
+	err := c.Print(&bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "invalid format provided (pdf)") {
+		t.Errorf("expected invalid format error, got %v", err)
+	}
+}
+
+func TestClient_PrintJSONIndent(t *testing.T) {
+	deps := []*Repository{{Name: "github.com/ribice/glice", License: "MIT", RiskScore: 1, Version: "v1.0.0"}}
+	tests := map[string]struct {
+		opts []Option
+		want string
+	}{
+		"object by default": {
+			want: `{"modules":[{"name":"github.com/ribice/glice","license":"MIT","license_spdx":"","risk_score":1,"Version":"v1.0.0"}],"summary":{"MIT":1}}` + "\n",
+		},
+		"compact legacy array": {
+			opts: []Option{WithLegacyJSON()},
+			want: `[{"name":"github.com/ribice/glice","license":"MIT","license_spdx":"","risk_score":1,"Version":"v1.0.0"}]` + "\n",
+		},
+		"indented": {
+			opts: []Option{WithJSONIndent("", "  "), WithLegacyJSON()},
+			want: `[
+  {
+    "name": "github.com/ribice/glice",
+    "license": "MIT",
+    "license_spdx": "",
+    "risk_score": 1,
+    "Version": "v1.0.0"
+  }
+]
+`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewClient(wd(), append(tt.opts, WithFormat("json"))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.dependencies = deps
+			output := &bytes.Buffer{}
+			if err := c.Print(output); err != nil {
+				t.Fatal(err)
+			}
+			if got := output.String(); got != tt.want {
+				t.Errorf("Print() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Write(t *testing.T) {
+	dir := t.TempDir()
+	cwd := wd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(cwd)
+
+	w := &bytes.Buffer{}
+	c, err := NewClient(cwd, WithFormat("json"), WithOutputs("file"), WithOutputWriter(w))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.dependencies = []*Repository{{Name: "github.com/ribice/glice", License: "MIT"}}
+	if err := c.Write(); err != nil {
+		t.Fatal(err)
+	}
+
+	bts, err := os.ReadFile(filepath.Join(dir, "dependencies.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Len() == 0 || !bytes.Equal(bts, w.Bytes()) {
+		t.Errorf("expected the same output in file and writer, got %q and %q", bts, w.String())
+	}
+}
+
+func TestClient_PrintTee(t *testing.T) {
+	dir := t.TempDir()
+	cwd := wd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(cwd)
+
+	tests := map[string]struct {
+		opts     []Option
+		wantFile string
+	}{
+		"default file":  {wantFile: "glice-output.json"},
+		"custom file":   {opts: []Option{WithOutputFile(filepath.Join(dir, "licenses.json"))}, wantFile: "licenses.json"},
+		"stdout output": {opts: []Option{WithOutput("stdout")}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewClient(cwd, append([]Option{WithFormat("json"), WithOutput("both")}, tt.opts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.dependencies = []*Repository{{Name: "github.com/ribice/glice", License: "MIT"}}
+			w := &bytes.Buffer{}
+			if err := c.printTee(w); err != nil {
+				t.Fatal(err)
+			}
+			if w.Len() == 0 {
+				t.Fatal("expected output in writer")
+			}
+			if tt.wantFile == "" {
+				return
+			}
+			bts, err := os.ReadFile(filepath.Join(dir, tt.wantFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(bts, w.Bytes()) {
+				t.Errorf("expected the same output in file and writer, got %q and %q", bts, w.String())
+			}
+		})
+	}
+}
+
+func TestClient_ApplyLicenseOverrides(t *testing.T) {
+	mirror := &Repository{Name: "example.com/mirror", License: "Other", Shortname: "Other"}
+	kept := &Repository{Name: "github.com/ribice/glice", License: "MIT", Shortname: "MIT", LicenseSPDX: "mit"}
+	c := &Client{LicenseOverrides: map[string]string{"example.com/mirror": "BSD-3-Clause"}}
+	c.applyLicenseOverrides([]*Repository{mirror, kept})
+
+	if mirror.License != "BSD-3-Clause" || mirror.LicenseSPDX != "BSD-3-Clause" || mirror.Category != CategoryPermissive ||
+		mirror.Shortname != "BSD-3-Clause" {
+		t.Errorf("override was not applied: %+v", mirror)
+	}
+	if kept.License != "MIT" || kept.LicenseSPDX != "mit" {
+		t.Errorf("dependency without override was changed: %+v", kept)
+	}
+}
+
+func TestClient_Dedupe(t *testing.T) {
+	dir := t.TempDir()
+	gomod := `module example.com/a
+
+go 1.18
+
+require (
+	github.com/ribice/kiss v1.2.0
+	github.com/ribice/kiss v1.10.0
+	github.com/fatih/color v1.17.0
+)
+`
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	modules, err := mod.Parse(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repos := make([]*Repository, len(modules))
+	for i, m := range modules {
+		repos[i] = &Repository{Name: m.Path, Version: m.Version}
+	}
+	logs := &bytes.Buffer{}
+	c := &Client{logger: slog.New(slog.NewTextHandler(logs, nil))}
+
+	var got []string
+	for _, r := range c.dedupe(repos) {
+		got = append(got, r.Name+"@"+r.Version)
+	}
+	want := []string{"github.com/ribice/kiss@v1.10.0", "github.com/fatih/color@v1.17.0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dedupe() = %v, want %v", got, want)
+	}
+	if !strings.Contains(logs.String(), "github.com/ribice/kiss@v1.2.0") {
+		t.Errorf("expected dropped duplicate to be logged, got %q", logs.String())
+	}
+}
+
+func TestClient_LoggerAttributes(t *testing.T) {
+	logs := &bytes.Buffer{}
+	c := &Client{
+		logger: slog.New(slog.NewJSONHandler(logs, nil)),
+		ignore: []string{"golang.org/x/*"},
+	}
+	c.filterIgnored([]*Repository{{Name: "golang.org/x/mod", Version: "v0.17.0"}})
+
+	var entry map[string]any
+	if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+		t.Fatalf("expected JSON log entry, got %q: %v", logs.String(), err)
+	}
+	if entry["level"] != "INFO" || entry["dependency"] != "golang.org/x/mod" || entry["version"] != "v0.17.0" {
+		t.Errorf("unexpected log entry: %v", entry)
+	}
+}
+
+func TestClient_PrintNDJSON(t *testing.T) {
+	c := &Client{format: "ndjson", output: "stdout", dependencies: []*Repository{
+		{Name: "github.com/ribice/glice", License: "MIT", Version: "v1.0.0"},
+		{Name: "github.com/fatih/color", License: "MIT", Version: "v1.17.0"},
+	}}
+	output := &bytes.Buffer{}
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one line per dependency, got %q", output.String())
+	}
+	for i, l := range lines {
+		var r Repository
+		if err := json.Unmarshal([]byte(l), &r); err != nil {
+			t.Fatal(err)
+		}
+		if r.Name != c.dependencies[i].Name {
+			t.Errorf("line %d = %s, want %s", i, r.Name, c.dependencies[i].Name)
+		}
+	}
+}
+
+func TestClient_PrintContext(t *testing.T) {
+	deps := []*Repository{
+		{Name: "github.com/ribice/glice", License: "MIT", Version: "v1.0.0"},
+		{Name: "github.com/fatih/color", License: "MIT", Version: "v1.17.0"},
+	}
+	for _, format := range []string{"table", "json", "csv", "ndjson", "markdown"} {
+		t.Run(format, func(t *testing.T) {
+			c := &Client{format: format, dependencies: deps}
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			output := &bytes.Buffer{}
+			if err := c.PrintContext(ctx, output); err != context.Canceled {
+				t.Errorf("expected context.Canceled, got %v", err)
+			}
+			if output.Len() != 0 {
+				t.Errorf("expected no output, got %q", output.String())
+			}
+		})
+	}
+
+	t.Run("cancelled between rows", func(t *testing.T) {
+		c := &Client{format: "ndjson", dependencies: deps}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		output := &cancelWriter{cancel: cancel}
+		if err := c.PrintContext(ctx, output); err != context.Canceled {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+		if got := strings.Count(output.String(), "\n"); got != 1 {
+			t.Errorf("expected one row before cancellation, got %q", output.String())
+		}
+	})
+}
+
+// cancelWriter cancels its context after the first write
+type cancelWriter struct {
+	bytes.Buffer
+	cancel context.CancelFunc
+}
+
+func (w *cancelWriter) Write(p []byte) (int, error) {
+	defer w.cancel()
+	return w.Buffer.Write(p)
+}
+
+func TestClient_PrintYAML(t *testing.T) {
+	c := &Client{format: "yaml", output: "stdout", dependencies: []*Repository{{
+		Name: "github.com/ribice/glice", URL: "https://github.com/ribice/glice", Host: "github.com", Author: "ribice",
+		Project: "glice", License: "MIT", Shortname: "MIT", Text: "bGljZW5zZS10ZXh0", Version: "v1.0.0",
+	}}}
+	output := &bytes.Buffer{}
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `- name: github.com/ribice/glice
+  url: https://github.com/ribice/glice
+  author: ribice
+  license: MIT
+  version: v1.0.0
+`
+	if got := output.String(); got != want {
+		t.Errorf("Print() = %q, want %q", got, want)
+	}
+}
+
 func TestClient_WriteLicensesToFile(t *testing.T) {
 	tests := map[string]struct {
 		dependencies   []*Repository
@@ -172,9 +795,16 @@ func TestClient_WriteLicensesToFile(t *testing.T) {
 			dependencies: []*Repository{{
 				Author:  "ribice",
 				Project: "glice",
-				Text:    "license-text",
+				Text:    "bGljZW5zZS10ZXh",
 			}},
 			wantErr: true},
+		"a dependency with plain license text": {
+			dependencies: []*Repository{{
+				Author:  "ribice",
+				Project: "glice",
+				Text:    "license-text",
+			}},
+			wantOutputFile: true},
 		"a dependency without license text": {
 			dependencies: []*Repository{{
 				Author:  "ribice",
@@ -214,6 +844,66 @@ func TestClient_WriteLicensesToFile(t *testing.T) {
 
 }
 
+func TestClient_WriteLicensesToFileOptions(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "third_party", "licenses")
+	c, err := NewClient(wd(), WithLicenseOutputDir(dir), WithLicenseFileTemplate("{{.Project}}-{{.LicenseSPDX}}.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.dependencies = []*Repository{{Author: "ribice", Project: "glice", LicenseSPDX: "mit", Text: "bGljZW5zZS10ZXh0"}}
+	if err := c.WriteLicensesToFile(); err != nil {
+		t.Fatal(err)
+	}
+	bts, err := os.ReadFile(filepath.Join(dir, "glice-mit.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bts) != "license-text" {
+		t.Errorf("unexpected license file content %q", bts)
+	}
+
+	if _, err := NewClient(wd(), WithLicenseFileTemplate("{{.Project")); err == nil {
+		t.Error("expected error for invalid license file template")
+	}
+}
+
+func TestClient_WriteLicensesWithHashes(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewClient(wd(), WithLicenseOutputDir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.dependencies = []*Repository{
+		{Author: "ribice", Project: "glice", Text: "bGljZW5zZS10ZXh0"},
+		{Author: "ribice", Project: "kiss"},
+		{Author: "fatih", Project: "color", Text: "license-text"},
+	}
+	if err := c.WriteLicensesWithHashes(); err != nil {
+		t.Fatal(err)
+	}
+
+	const hash = "a7b9a296df67a2985f1189eb702682db4e805942318ffd9f65d013000a7ed927"
+	got, err := os.ReadFile(filepath.Join(dir, checksumsFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := hash + "  ribice-glice-license.MD\n" + hash + "  fatih-color-license.MD\n"
+	if string(got) != want {
+		t.Errorf("CHECKSUMS.txt = %q, want %q", got, want)
+	}
+	if c.dependencies[0].LicenseHash != hash || c.dependencies[1].LicenseHash != "" {
+		t.Errorf("unexpected license hashes %q and %q", c.dependencies[0].LicenseHash, c.dependencies[1].LicenseHash)
+	}
+
+	bts, err := json.Marshal(c.dependencies[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(bts), `"license_hash":"`+hash+`"`) {
+		t.Errorf("expected license_hash in JSON, got %s", bts)
+	}
+}
+
 func TestListRepositories(t *testing.T) {
 	_, err := ListRepositories("path", false)
 	if err == nil {
@@ -240,6 +930,118 @@ func TestListRepositories(t *testing.T) {
 	}
 }
 
+// go.sum is checked only by VerifyGoSum, as it legitimately holds hashes of test-only dependencies
+func TestListRepositoriesSkipsGoSum(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/a\n\ngo 1.18\n\nrequire github.com/fatih/color v1.17.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gosum := "github.com/fatih/color v1.16.0 h1:zmkK9Ngbjj+K0yRhTVONQh1p/HknKYSlNT+vZCzyokM=\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.sum"), []byte(gosum), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer slog.SetDefault(slog.Default())
+	logs := &bytes.Buffer{}
+	slog.SetDefault(slog.New(slog.NewTextHandler(logs, nil)))
+	if _, err := ListRepositories(dir, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := logs.String(); got != "" {
+		t.Errorf("expected no go.sum warning, got %q", got)
+	}
+	if res := ListRepositoriesVerbose(dir, false); len(res.Errors) > 0 {
+		t.Errorf("expected no go.sum errors, got %v", res.Errors)
+	}
+}
+
+func TestListRepositoriesVerbose(t *testing.T) {
+	if res := ListRepositoriesVerbose("path", false); len(res.Repositories) > 0 || len(res.Errors) != 1 {
+		t.Errorf("expected single go.mod error, got %+v", res)
+	}
+
+	dir := t.TempDir()
+	goMod := "module example.com/a\n\ngo 1.21\n\nrequire (\n\tgithub.com/ribice/kiss v1.0.0\n\t\"github.com/bad path\" v1.0.0\n\tgithub.com/ribice/glice/ v1.0.0\n)\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	res := ListRepositoriesVerbose(dir, false)
+	if len(res.Repositories) != 1 || res.Repositories[0].Name != "github.com/ribice/kiss" {
+		t.Errorf("expected only valid dependency to be listed, got %+v", res.Repositories)
+	}
+	var errs []string
+	for _, err := range res.Errors {
+		errs = append(errs, err.Error())
+	}
+	want := []string{
+		`malformed module path "github.com/bad path": invalid char ' '`,
+		`malformed module path "github.com/ribice/glice/": trailing slash`,
+	}
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("Errors = %q, want %q", errs, want)
+	}
+
+	c, err := NewClient(dir, WithDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseDependencies(false, false); err != nil || len(c.dependencies) != 1 {
+		t.Errorf("expected malformed dependencies to be skipped, got %+v, %v", c.dependencies, err)
+	}
+}
+
+func TestListAllRepositories(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":       "module example.com/root\n\ngo 1.21\n\nrequire github.com/ribice/kiss v1.0.0\n",
+		"svc/a/go.mod": "module example.com/a\n\ngo 1.21\n\nrequire (\n\texample.com/root v0.0.0\n\tgithub.com/ribice/kiss v1.1.0\n\tgithub.com/fatih/color v1.17.0\n)\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repos, err := ListAllRepositories(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range repos {
+		got = append(got, r.Name+"@"+r.Version)
+	}
+	want := []string{"github.com/ribice/kiss@v1.1.0", "github.com/fatih/color@v1.17.0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListAllRepositories() = %v, want %v", got, want)
+	}
+
+	c, err := NewClientForRoot(dir, WithDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseDependencies(false, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.dependencies) != 2 {
+		t.Errorf("expected 2 dependencies of all modules, got %+v", c.dependencies)
+	}
+	// root without go.mod
+	c, err = NewClientForRoot(filepath.Join(dir, "svc"), WithDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseDependencies(false, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.dependencies) != 3 {
+		t.Errorf("expected 3 dependencies of svc/a, got %+v", c.dependencies)
+	}
+}
+
 func TestNewClient(t *testing.T) {
 	tests := map[string]struct {
 		path    string
@@ -270,7 +1072,7 @@ func TestNewClient(t *testing.T) {
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			_, err := NewClient(tt.path, tt.format, tt.output)
+			_, err := NewClient(tt.path, WithFormat(tt.format), WithOutput(tt.output))
 			if (err != nil) != tt.wantErr {
 				t.Errorf("NewClient() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -279,35 +1081,98 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestRepoFromRedirect(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer target.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/kiss" {
+			http.Redirect(w, r, target.URL+"/ribice/kiss", http.StatusMovedPermanently)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	r := repoFromRedirect(srv.Client(), srv.URL+"/kiss", slog.Default())
+	host := strings.TrimPrefix(target.URL, "http://")
+	want := &Repository{URL: "https://" + host + "/ribice/kiss", Host: host, Author: "ribice", Project: "kiss"}
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("repoFromRedirect() = %+v, want %+v", r, want)
+	}
+	if r := repoFromRedirect(srv.Client(), srv.URL+"/missing", slog.Default()); r != nil {
+		t.Errorf("expected nil repository for missing page, got %+v", r)
+	}
+}
+
 func TestGetRepository(t *testing.T) {
 	tests := map[string]struct {
 		module string
+		hosts  forgeHosts
 		want   *Repository
 	}{
 		"github.com/ribice": {
 			module: "github.com/ribice",
-			want:   &Repository{Name: "github.com/ribice"},
+			want:   &Repository{Name: "github.com/ribice", PURL: "pkg:golang/github.com/ribice"},
 		},
 		"github.com/ribice/glice": {
 			module: "github.com/ribice/glice",
-			want:   &Repository{Name: "github.com/ribice/glice", URL: "https://github.com/ribice/glice", Host: "github.com", Author: "ribice", Project: "glice"},
+			want:   &Repository{Name: "github.com/ribice/glice", URL: "https://github.com/ribice/glice", Host: "github.com", Author: "ribice", Project: "glice", PURL: "pkg:golang/github.com/ribice/glice"},
 		},
 		"gopkg.in/ribice": {
 			module: "gopkg.in/ribice",
-			want:   &Repository{Name: "gopkg.in/ribice"},
+			want:   &Repository{Name: "gopkg.in/ribice", PURL: "pkg:golang/gopkg.in/ribice"},
 		},
 		"gopkg.in/ribice/glice": {
 			module: "gopkg.in/ribice/glice",
-			want:   &Repository{Name: "gopkg.in/ribice/glice", URL: "https://github.com/ribice/glice", Host: "github.com", Author: "ribice", Project: "glice"},
+			want:   &Repository{Name: "gopkg.in/ribice/glice", URL: "https://github.com/ribice/glice", Host: "github.com", Author: "ribice", Project: "glice", PURL: "pkg:golang/gopkg.in/ribice/glice"},
+		},
+		"gopkg.in/yaml.v3": {
+			module: "gopkg.in/yaml.v3",
+			want:   &Repository{Name: "gopkg.in/yaml.v3", URL: "https://github.com/go-yaml/yaml", Host: "github.com", Author: "go-yaml", Project: "yaml", PURL: "pkg:golang/gopkg.in/yaml.v3"},
+		},
+		"gopkg.in/check.v1-unstable": {
+			module: "gopkg.in/check.v1-unstable",
+			want:   &Repository{Name: "gopkg.in/check.v1-unstable", URL: "https://github.com/go-check/check", Host: "github.com", Author: "go-check", Project: "check", PURL: "pkg:golang/gopkg.in/check.v1-unstable"},
+		},
+		"gopkg.in/src-d/go-git.v4": {
+			module: "gopkg.in/src-d/go-git.v4",
+			want:   &Repository{Name: "gopkg.in/src-d/go-git.v4", URL: "https://github.com/src-d/go-git", Host: "github.com", Author: "src-d", Project: "go-git", PURL: "pkg:golang/gopkg.in/src-d/go-git.v4"},
 		},
 		"fmt": {
 			module: "fmt",
-			want:   &Repository{Name: "fmt"},
+			want:   &Repository{Name: "fmt", PURL: "pkg:golang/fmt"},
+		},
+		"gitlab.com": {
+			module: "gitlab.com/ribice/glice/v2",
+			want:   &Repository{Name: "gitlab.com/ribice/glice/v2", URL: "https://gitlab.com/ribice/glice", Host: "gitlab.com", Author: "ribice", Project: "glice", PURL: "pkg:golang/gitlab.com/ribice/glice/v2"},
+		},
+		"bitbucket.org": {
+			module: "bitbucket.org/ribice/glice",
+			want:   &Repository{Name: "bitbucket.org/ribice/glice", URL: "https://bitbucket.org/ribice/glice", Host: "bitbucket.org", Author: "ribice", Project: "glice", PURL: "pkg:golang/bitbucket.org/ribice/glice"},
+		},
+		"self-hosted gitlab": {
+			module: "gitlab.example.com/ribice/glice",
+			hosts:  forgeHosts{gitlab: []string{"gitlab.example.com"}},
+			want:   &Repository{Name: "gitlab.example.com/ribice/glice", URL: "https://gitlab.example.com/ribice/glice", Host: "gitlab.example.com", Author: "ribice", Project: "glice", PURL: "pkg:golang/gitlab.example.com/ribice/glice"},
+		},
+		"gitea": {
+			module: "codeberg.org/ribice/glice",
+			hosts:  forgeHosts{gitea: []string{"codeberg.org"}},
+			want:   &Repository{Name: "codeberg.org/ribice/glice", URL: "https://codeberg.org/ribice/glice", Host: "gitea", HostURL: "codeberg.org", Author: "ribice", Project: "glice", PURL: "pkg:golang/codeberg.org/ribice/glice"},
+		},
+		"vanity import": {
+			module: "example.com/kiss",
+			want:   &Repository{Name: "example.com/kiss", URL: "https://github.com/ribice/kiss", Host: "github.com", Author: "ribice", Project: "kiss", PURL: "pkg:golang/example.com/kiss"},
 		},
 	}
+	t.Setenv("GOPROXY", "off")
+	defer func(hc *http.Client) { goGetClient = hc }(goGetClient)
+	goGetClient = NewMockHTTPClient(map[string]string{
+		"https://example.com/kiss?go-get=1": `<html><head><meta name="go-import" content="example.com/kiss git https://github.com/ribice/kiss"></head></html>`,
+	})
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			if got := getRepository(tt.module); !reflect.DeepEqual(got, tt.want) {
+			if got := getRepository(module.Version{Path: tt.module}, nil, tt.hosts); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("getRepository() = %v, want %v", got, tt.want)
 			}
 		})
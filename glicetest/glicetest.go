@@ -0,0 +1,41 @@
+// Package glicetest provides clients with fixed dependencies for testing tools built on glice,
+// without go.mod or network access.
+package glicetest
+
+import (
+	"strings"
+
+	"github.com/ribice/glice/v2"
+)
+
+// NewMockClient returns client whose dependencies are repos, as if they were parsed from go.mod.
+// It prints a table to stdout without colors unless configured by opts. ParseDependencies would
+// replace repos with dependencies of go.mod in current directory, without fetching their licenses.
+// It panics if opts are invalid.
+func NewMockClient(repos []*glice.Repository, opts ...glice.Option) *glice.Client {
+	opts = append([]glice.Option{glice.WithDependencies(repos...), glice.WithColor(false), glice.WithDryRun()}, opts...)
+	c, err := glice.NewClient("", opts...)
+	if err != nil {
+		panic("glicetest: " + err.Error())
+	}
+	return c
+}
+
+// MockRepository returns repository of module name with license, at version v1.0.0. Host, author
+// and project are taken from name when it has at least three elements, e.g. github.com/author/project.
+func MockRepository(name, license string) *glice.Repository {
+	r := &glice.Repository{
+		Name:        name,
+		URL:         "https://" + name,
+		License:     license,
+		Shortname:   license,
+		LicenseSPDX: license,
+		Category:    glice.LicenseCategory(license),
+		RiskScore:   glice.LicenseRiskScore(license),
+		Version:     "v1.0.0",
+	}
+	if elems := strings.Split(name, "/"); len(elems) >= 3 {
+		r.Host, r.Author, r.Project = elems[0], elems[1], elems[2]
+	}
+	return r
+}
@@ -0,0 +1,92 @@
+package glicetest
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ribice/glice/v2"
+)
+
+func mockRepos() []*glice.Repository {
+	return []*glice.Repository{
+		MockRepository("github.com/ribice/kiss", "MIT"),
+		MockRepository("github.com/fatih/color", "Apache-2.0"),
+		MockRepository("gitlab.com/gnu/tool", "GPL-3.0"),
+		MockRepository("example.com/unknown", ""),
+	}
+}
+
+func TestMockRepository(t *testing.T) {
+	got := MockRepository("github.com/ribice/kiss/v2", "MIT")
+	want := &glice.Repository{
+		Name:        "github.com/ribice/kiss/v2",
+		URL:         "https://github.com/ribice/kiss/v2",
+		Host:        "github.com",
+		Author:      "ribice",
+		Project:     "kiss",
+		License:     "MIT",
+		Shortname:   "MIT",
+		LicenseSPDX: "MIT",
+		Category:    glice.CategoryPermissive,
+		RiskScore:   glice.LicenseRiskScore("MIT"),
+		Version:     "v1.0.0",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MockRepository() = %+v, want %+v", got, want)
+	}
+	if r := MockRepository("example.com/a", "MIT"); r.Host != "" || r.Author != "" {
+		t.Errorf("expected no host and author for short module path, got %+v", r)
+	}
+}
+
+func TestNewMockClient_Print(t *testing.T) {
+	formats := []string{"table", "json", "csv", "spdx-json", "spdx-tv", "html", "markdown", "yaml", "xml", "junit",
+		"cyclonedx-json", "cyclonedx-xml", "sarif", "ndjson", "dot", "compat", "fossa", "benchmark"}
+	for _, f := range formats {
+		t.Run(f, func(t *testing.T) {
+			c := NewMockClient(mockRepos(), glice.WithFormat(f))
+			var buf bytes.Buffer
+			if err := c.Print(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if f != "benchmark" && f != "compat" && !strings.Contains(buf.String(), "example.com/unknown") {
+				t.Errorf("expected dependency in %s output:\n%s", f, buf.String())
+			}
+		})
+	}
+}
+
+func TestNewMockClient_Filter(t *testing.T) {
+	c := NewMockClient(mockRepos())
+	names := func(repos []*glice.Repository) []string {
+		var n []string
+		for _, r := range repos {
+			n = append(n, r.Name)
+		}
+		return n
+	}
+
+	if got := names(c.FilterUnknown()); !reflect.DeepEqual(got, []string{"example.com/unknown"}) {
+		t.Errorf("FilterUnknown() = %v", got)
+	}
+	if got := names(c.FilterByCategory(glice.CategoryPermissive)); !reflect.DeepEqual(got, []string{"github.com/ribice/kiss", "github.com/fatih/color"}) {
+		t.Errorf("FilterByCategory() = %v", got)
+	}
+	if got := names(c.FilterByHost("gitlab.com")); !reflect.DeepEqual(got, []string{"gitlab.com/gnu/tool"}) {
+		t.Errorf("FilterByHost() = %v", got)
+	}
+
+	c.BlockedLicenses = []string{"gpl-3.0"}
+	if blocked, err := c.CheckBlocked(); err == nil || !reflect.DeepEqual(names(blocked), []string{"gitlab.com/gnu/tool"}) {
+		t.Errorf("CheckBlocked() = %v, %v", names(blocked), err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for invalid option")
+		}
+	}()
+	NewMockClient(nil, glice.WithFormat("invalid"))
+}
@@ -0,0 +1,127 @@
+package glice
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// graphQLBatchSize is maximum number of repositories fetched by a single GraphQL query
+const graphQLBatchSize = 20
+
+type graphQLRepository struct {
+	LicenseInfo *struct {
+		SpdxID string `graphql:"spdxId"`
+		Name   string
+		Body   string
+	}
+	// activity is fetched along with license as it doesn't cost extra requests
+	IsArchived     bool
+	PushedAt       *githubv4.DateTime
+	StargazerCount int
+}
+
+// graphQLURL returns GraphQL endpoint of GitHub Enterprise Server with REST API at baseURL,
+// e.g. https://github.example.com/api/graphql for https://github.example.com/api/v3/
+func graphQLURL(baseURL string) string {
+	u := strings.TrimSuffix(baseURL, "/")
+	return strings.TrimSuffix(u, "/v3") + "/graphql"
+}
+
+// graphQLLicenseQuery builds query fetching license info of all repos, aliased as r0, r1...
+// Query struct is built with reflection as githubv4 doesn't support dynamic number of fields.
+func graphQLLicenseQuery(repos []*Repository) (reflect.Value, map[string]interface{}) {
+	fields := make([]reflect.StructField, len(repos))
+	vars := make(map[string]interface{}, 2*len(repos))
+	for i, r := range repos {
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("R%d", i),
+			Type: reflect.TypeOf(&graphQLRepository{}),
+			Tag:  reflect.StructTag(fmt.Sprintf(`graphql:"r%d: repository(owner: $owner%d, name: $name%d)"`, i, i, i)),
+		}
+		vars[fmt.Sprintf("owner%d", i)] = githubv4.String(r.Author)
+		vars[fmt.Sprintf("name%d", i)] = githubv4.String(r.Project)
+	}
+	return reflect.New(reflect.StructOf(fields)), vars
+}
+
+// getGitHubLicenses fetches licenses of GitHub repos with a single GraphQL query, returning repos
+// whose license couldn't be fetched (e.g. they don't exist) so they can be fetched with REST API.
+// Query errors are returned along with repos, as licenses of other repos are still set.
+func (gc *gitClient) getGitHubLicenses(ctx context.Context, repos []*Repository) ([]*Repository, error) {
+	q, vars := graphQLLicenseQuery(repos)
+	rctx, cancel := gc.requestContext(ctx)
+	err := gc.ghv4.Query(rctx, q.Interface(), vars)
+	cancel()
+
+	var missing []*Repository
+	for i, r := range repos {
+		res := q.Elem().Field(i).Interface().(*graphQLRepository)
+		if res == nil {
+			missing = append(missing, r)
+			continue
+		}
+
+		key := "other"
+		if li := res.LicenseInfo; li != nil && li.SpdxID != "" && li.SpdxID != spdxNoAssertion {
+			key = strings.ToLower(li.SpdxID)
+			r.Text = base64.StdEncoding.EncodeToString([]byte(li.Body))
+		}
+		setLicense(r, key)
+		classifyLicense(r)
+		r.Stars = res.StargazerCount
+		r.IsArchived = res.IsArchived
+		if res.PushedAt != nil {
+			pushed := res.PushedAt.Time
+			r.PushedAt = &pushed
+		}
+		gc.starGitHub(ctx, r)
+	}
+	return missing, err
+}
+
+// fetchGitHubBatches fetches licenses of GitHub repositories in batches of graphQLBatchSize,
+// returning repositories that still have to be fetched one by one
+func (c *Client) fetchGitHubBatches(ctx context.Context, gc *gitClient, repos []*Repository, fetched func(*Repository)) []*Repository {
+	if gc.ghv4 == nil {
+		c.log().Warn("GitHub GraphQL API requires API key, falling back to REST API")
+		return repos
+	}
+
+	var gh, rest []*Repository
+	for _, r := range repos {
+		if r.Host == "github.com" {
+			gh = append(gh, r)
+		} else {
+			rest = append(rest, r)
+		}
+	}
+	for i := 0; i < len(gh); i += graphQLBatchSize {
+		batch := gh[i:min(i+graphQLBatchSize, len(gh))]
+		c.log().Info("Fetching licenses with GraphQL", "count", len(batch))
+		missing, err := gc.getGitHubLicenses(ctx, batch)
+		if err != nil {
+			c.log().Warn("Could not fetch all licenses with GraphQL, falling back to REST API", "error", err)
+		}
+		for _, r := range batch {
+			if !containsRepo(missing, r) {
+				fetched(r)
+			}
+		}
+		rest = append(rest, missing...)
+	}
+	return rest
+}
+
+func containsRepo(repos []*Repository, r *Repository) bool {
+	for _, v := range repos {
+		if v == r {
+			return true
+		}
+	}
+	return false
+}
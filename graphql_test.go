@@ -0,0 +1,135 @@
+package glice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGraphQLURL(t *testing.T) {
+	tests := map[string]string{
+		"https://github.example.com/api/v3/": "https://github.example.com/api/graphql",
+		"https://github.example.com/api/v3":  "https://github.example.com/api/graphql",
+		"https://github.example.com/api":     "https://github.example.com/api/graphql",
+	}
+	for in, want := range tests {
+		if got := graphQLURL(in); got != want {
+			t.Errorf("graphQLURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// graphQLServer responds to license queries as GitHub GraphQL API does, repos missing from
+// licenses are reported as not found
+func graphQLServer(t *testing.T, licenses map[string]string, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/graphql" {
+			http.NotFound(w, r)
+			return
+		}
+		*requests++
+		var in struct {
+			Query     string
+			Variables map[string]string
+		}
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Fatal(err)
+		}
+
+		data := map[string]interface{}{}
+		var errs []map[string]string
+		for i := 0; ; i++ {
+			owner, ok := in.Variables[fmt.Sprintf("owner%d", i)]
+			if !ok {
+				break
+			}
+			if !strings.Contains(in.Query, fmt.Sprintf("r%d: repository(owner: $owner%d, name: $name%d)", i, i, i)) {
+				t.Errorf("unexpected query %s", in.Query)
+			}
+			name := owner + "/" + in.Variables[fmt.Sprintf("name%d", i)]
+			spdx, ok := licenses[name]
+			if !ok {
+				data[fmt.Sprintf("r%d", i)] = nil
+				errs = append(errs, map[string]string{"message": "Could not resolve to a Repository with the name '" + name + "'."})
+				continue
+			}
+			data[fmt.Sprintf("r%d", i)] = map[string]interface{}{
+				"licenseInfo":    map[string]string{"spdxId": spdx, "name": spdx + " License", "body": "license-text"},
+				"isArchived":     spdx == "NOASSERTION",
+				"pushedAt":       "2020-01-02T03:04:05Z",
+				"stargazerCount": 42,
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data, "errors": errs})
+	}))
+}
+
+func TestGitHubGraphQLAPI(t *testing.T) {
+	var requests int
+	srv := graphQLServer(t, map[string]string{"ribice/kiss": "MIT", "some/custom": "NOASSERTION"}, &requests)
+	defer srv.Close()
+
+	c := context.Background()
+	gc := newGitClient(c, map[string]string{"github.com": "apikey"}, false, withGitHubBaseURL(srv.URL+"/api/v3"))
+
+	kiss := &Repository{Host: "github.com", Author: "ribice", Project: "kiss"}
+	custom := &Repository{Host: "github.com", Author: "some", Project: "custom"}
+	gone := &Repository{Host: "github.com", Author: "some", Project: "gone"}
+	missing, err := gc.getGitHubLicenses(c, []*Repository{kiss, custom, gone})
+	if err == nil {
+		t.Error("expected error for missing repository")
+	}
+	if len(missing) != 1 || missing[0] != gone {
+		t.Errorf("expected only missing repository to be returned, got %v", missing)
+	}
+	if kiss.License != "MIT" || kiss.LicenseSPDX != "MIT" || kiss.Category != CategoryPermissive || kiss.Text != "bGljZW5zZS10ZXh0" {
+		t.Errorf("GraphQL API did not return correct license, got %+v", kiss)
+	}
+	if custom.License != "Other" {
+		t.Errorf("expected unrecognized license to be Other, got %+v", custom)
+	}
+	if kiss.Stars != 42 || kiss.IsArchived || !custom.IsArchived || custom.PushedAt == nil || custom.PushedAt.Year() != 2020 {
+		t.Errorf("GraphQL API did not return correct activity, got %+v and %+v", kiss, custom)
+	}
+}
+
+func TestClient_FetchGitHubBatches(t *testing.T) {
+	licenses := map[string]string{}
+	var repos []*Repository
+	for i := 0; i < 2*graphQLBatchSize+1; i++ {
+		project := fmt.Sprintf("project%d", i)
+		licenses["ribice/"+project] = "MIT"
+		repos = append(repos, &Repository{Host: "github.com", Author: "ribice", Project: project})
+	}
+	gitlab := &Repository{Host: "gitlab.com", Author: "xanzy", Project: "go-gitlab"}
+	gone := &Repository{Host: "github.com", Author: "some", Project: "gone"}
+	repos = append(repos, gitlab, gone)
+
+	var requests int
+	srv := graphQLServer(t, licenses, &requests)
+	defer srv.Close()
+
+	c := context.Background()
+	gc := newGitClient(c, map[string]string{"github.com": "apikey"}, false, withGitHubBaseURL(srv.URL+"/api/v3"))
+
+	var fetched int
+	rest := (&Client{}).fetchGitHubBatches(c, gc, repos, func(*Repository) { fetched++ })
+	if requests != 3 {
+		t.Errorf("expected 3 GraphQL requests for %d GitHub repositories, got %d", len(repos)-1, requests)
+	}
+	if fetched != 2*graphQLBatchSize+1 {
+		t.Errorf("expected %d fetched repositories, got %d", 2*graphQLBatchSize+1, fetched)
+	}
+	if len(rest) != 2 || rest[0] != gitlab || rest[1] != gone {
+		t.Errorf("expected non-GitHub and missing repositories to be left for REST API, got %v", rest)
+	}
+
+	noKey := newGitClient(c, map[string]string{}, false, withGitHubBaseURL(srv.URL+"/api/v3"))
+	if rest := (&Client{}).fetchGitHubBatches(c, noKey, repos, func(*Repository) { fetched++ }); len(rest) != len(repos) {
+		t.Errorf("expected all repositories to be left for REST API without API key, got %d", len(rest))
+	}
+}
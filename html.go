@@ -0,0 +1,63 @@
+package glice
+
+import (
+	"html/template"
+	"io"
+)
+
+var htmlTemplate = template.Must(template.New("html").Funcs(template.FuncMap{
+	"licenseClass": htmlLicenseClass,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} - dependency licenses</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292e; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #d1d5da; padding: 6px 12px; text-align: left; }
+th { background: #f6f8fa; }
+a { color: #0366d6; text-decoration: none; }
+.license-permissive { color: #22863a; font-weight: bold; }
+.license-copyleft { color: #cb2431; font-weight: bold; }
+.license-unknown { color: #b08800; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+<thead>
+<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+</thead>
+<tbody>
+{{- range .Dependencies}}
+<tr><td>{{.Name}}</td><td><a href="{{.URL}}">{{.URL}}</a></td><td class="license-{{licenseClass .License}}">{{.License}}</td><td>{{.Version}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+</body>
+</html>
+`))
+
+// htmlLicenseClass groups license categories into permissive, copyleft and unknown
+func htmlLicenseClass(license string) string {
+	switch LicenseCategory(license) {
+	case CategoryPermissive, CategoryPublicDomain:
+		return "permissive"
+	case CategoryWeakCopyleft, CategoryStrongCopyleft, CategoryNetworkCopyleft:
+		return "copyleft"
+	}
+	return "unknown"
+}
+
+func (c *Client) printHTML(writeTo io.Writer) error {
+	return htmlTemplate.Execute(writeTo, struct {
+		Title        string
+		Header       []string
+		Dependencies []*Repository
+	}{
+		Title:        c.moduleName(),
+		Header:       headerRow,
+		Dependencies: c.dependencies,
+	})
+}
@@ -0,0 +1,35 @@
+package glice
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestClient_PrintHTML(t *testing.T) {
+	c := &Client{path: wd(), format: "html", output: "stdout", dependencies: []*Repository{
+		{Name: "github.com/ribice/glice", URL: "https://github.com/ribice/glice", License: "MIT", Version: "v1.0.0"},
+		{Name: "github.com/some/gpl", URL: "https://github.com/some/gpl", License: "GPL-3.0"},
+		{Name: "github.com/<script>", License: "proprietary"},
+	}}
+
+	output := &bytes.Buffer{}
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+
+	got := output.String()
+	for _, want := range []string{
+		`<meta charset="utf-8">`,
+		`<title>github.com/ribice/glice/v2 - dependency licenses</title>`,
+		`<a href="https://github.com/ribice/glice">https://github.com/ribice/glice</a>`,
+		`<td class="license-permissive">MIT</td>`,
+		`<td class="license-copyleft">GPL-3.0</td>`,
+		`<td class="license-unknown">proprietary</td>`,
+		`github.com/&lt;script&gt;`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package glice
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// printJUnit writes a test report where each dependency is a test case,
+// failing when its license is in BlockedLicenses.
+func (c *Client) printJUnit(writeTo io.Writer) error {
+	suite := junitTestSuite{Name: c.moduleName(), Tests: len(c.dependencies)}
+	for _, d := range c.dependencies {
+		tc := junitTestCase{Name: d.Name, ClassName: spdxLicense(d)}
+		if containsFold(c.BlockedLicenses, d.License) {
+			tc.Failure = &junitFailure{
+				Message: fmt.Sprintf("blocked license %s", d.License),
+				Type:    "BlockedLicense",
+				Text:    fmt.Sprintf("%s %s uses blocked license %s", d.Name, d.Version, d.License),
+			}
+			suite.Failures++
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	if _, err := io.WriteString(writeTo, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(writeTo)
+	enc.Indent("", "  ")
+	err := enc.Encode(junitTestSuites{
+		Name:     "glice",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{suite},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(writeTo, "\n")
+	return err
+}
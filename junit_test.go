@@ -0,0 +1,35 @@
+package glice
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+)
+
+func TestClient_PrintJUnit(t *testing.T) {
+	c := &Client{path: wd(), format: "junit", output: "stdout", BlockedLicenses: []string{"gpl-3.0"}, dependencies: []*Repository{
+		{Name: "github.com/ribice/glice", License: "MIT", Version: "v1.0.0"},
+		{Name: "github.com/some/gpl", License: "GPL-3.0", Version: "v0.1.0"},
+	}}
+
+	output := &bytes.Buffer{}
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+
+	var got junitTestSuites
+	if err := xml.Unmarshal(output.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Tests != 2 || got.Failures != 1 || len(got.Suites) != 1 {
+		t.Fatalf("unexpected test suites: %+v", got)
+	}
+
+	cases := got.Suites[0].TestCases
+	if cases[0].Name != "github.com/ribice/glice" || cases[0].ClassName != "MIT" || cases[0].Failure != nil {
+		t.Errorf("unexpected passing test case: %+v", cases[0])
+	}
+	if cases[1].Name != "github.com/some/gpl" || cases[1].ClassName != "GPL-3.0" || cases[1].Failure == nil {
+		t.Errorf("unexpected failing test case: %+v", cases[1])
+	}
+}
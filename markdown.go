@@ -0,0 +1,30 @@
+package glice
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\n", " ")
+
+// printMarkdown writes dependencies as GitHub-Flavored Markdown table.
+// License is used instead of Shortname as Markdown doesn't support ANSI colors.
+func (c *Client) printMarkdown(ctx context.Context, writeTo io.Writer) error {
+	w := &errWriter{w: writeTo}
+	w.printf("| %s |\n", strings.Join(headerRow, " | "))
+	w.printf("|%s\n", strings.Repeat(" --- |", len(headerRow)))
+	for _, d := range c.dependencies {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		url := ""
+		if d.URL != "" {
+			url = fmt.Sprintf("[%s](%s)", markdownEscaper.Replace(d.URL), d.URL)
+		}
+		w.printf("| %s | %s | %s | %s |\n",
+			markdownEscaper.Replace(d.Name), url, markdownEscaper.Replace(d.License), markdownEscaper.Replace(d.Version))
+	}
+	return w.err
+}
@@ -0,0 +1,26 @@
+package glice
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestClient_PrintMarkdown(t *testing.T) {
+	c := &Client{format: "markdown", output: "stdout", dependencies: []*Repository{
+		{Name: "github.com/ribice/glice", URL: "https://github.com/ribice/glice", License: "MIT", Shortname: "\x1b[32mMIT\x1b[0m", Version: "v1.0.0"},
+		{Name: "example.com/pipe|name", License: "Other"},
+	}}
+
+	output := &bytes.Buffer{}
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "| Dependency | RepoURL | License | Version |\n" +
+		"| --- | --- | --- | --- |\n" +
+		"| github.com/ribice/glice | [https://github.com/ribice/glice](https://github.com/ribice/glice) | MIT | v1.0.0 |\n" +
+		"| example.com/pipe\\|name |  | Other |  |\n"
+	if got := output.String(); got != want {
+		t.Errorf("Print() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,49 @@
+package glice
+
+import (
+	"io"
+	"net/http"
+	"strings"
+)
+
+// NewMockHTTPClient returns HTTP client which responds to requests for URLs in responses with their
+// bodies, and with 404 Not Found to any other request. It can be used with WithHTTPClient to test
+// license fetching without network access. URLs are matched with query string first, then without it.
+func NewMockHTTPClient(responses map[string]string) *http.Client {
+	return &http.Client{Transport: mockTransport(responses)}
+}
+
+type mockTransport map[string]string
+
+func (m mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	u := *req.URL
+	body, ok := m[u.String()]
+	if !ok {
+		u.RawQuery = ""
+		body, ok = m[u.String()]
+	}
+
+	status := http.StatusOK
+	if !ok {
+		status, body = http.StatusNotFound, `{"message": "Not Found"}`
+	}
+	return &http.Response{
+		Status:        http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{contentType(body)}},
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// contentType guesses content type of canned response, colly only parses HTML responses
+func contentType(body string) string {
+	if strings.HasPrefix(strings.TrimSpace(body), "<") {
+		return "text/html; charset=utf-8"
+	}
+	return "application/json"
+}
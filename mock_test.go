@@ -0,0 +1,70 @@
+package glice
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestNewMockHTTPClient(t *testing.T) {
+	hc := NewMockHTTPClient(map[string]string{
+		"https://example.com/license":   "MIT",
+		"https://example.com/go?go-get": "query",
+	})
+	tests := map[string]struct {
+		url        string
+		wantStatus int
+		wantBody   string
+	}{
+		"exact":          {url: "https://example.com/license", wantStatus: http.StatusOK, wantBody: "MIT"},
+		"without query":  {url: "https://example.com/license?page=1", wantStatus: http.StatusOK, wantBody: "MIT"},
+		"with query":     {url: "https://example.com/go?go-get", wantStatus: http.StatusOK, wantBody: "query"},
+		"unknown url":    {url: "https://example.com/other", wantStatus: http.StatusNotFound},
+		"different host": {url: "https://example.org/license", wantStatus: http.StatusNotFound},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := hc.Get(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantBody != "" && string(body) != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestMockHTTPClient_GetLicense(t *testing.T) {
+	hc := NewMockHTTPClient(map[string]string{
+		"https://api.github.com/repos/ribice/kiss/license": `{"content": "bGljZW5zZS10ZXh0", "download_url": "https://raw.githubusercontent.com/ribice/kiss/master/LICENSE", "sha": "0123456789abcdef0123456789abcdef01234567", "license": {"key": "mit", "name": "MIT License"}}`,
+		"https://pkg.go.dev/example.com/vanity": `<html><body>
+<span data-test-id="UnitHeader-licenses"><a href="/example.com/vanity?tab=licenses">BSD-3-Clause</a></span>
+</body></html>`,
+	})
+	c := context.Background()
+	gc := newGitClient(c, map[string]string{}, false, withHTTPClient(hc))
+
+	gh := &Repository{Host: "github.com", Author: "ribice", Project: "kiss"}
+	if err := gc.GetLicense(c, gh); err != nil {
+		t.Fatal(err)
+	}
+	if gh.License != "MIT" || gh.Text != "bGljZW5zZS10ZXh0" || gh.LicenseURL != "https://raw.githubusercontent.com/ribice/kiss/master/LICENSE" ||
+		gh.CommitSHA != "0123456789abcdef0123456789abcdef01234567" {
+		t.Errorf("expected GitHub license from mocked response, got %+v", gh)
+	}
+
+	other := &Repository{Name: "example.com/vanity", Host: "pkg.go.dev"}
+	if err := gc.GetLicense(c, other); err != nil {
+		t.Fatal(err)
+	}
+	if other.License != "BSD-3-Clause" || other.LicenseSPDX != "BSD-3-Clause" || other.LicenseURL != "https://pkg.go.dev/example.com/vanity?tab=licenses" {
+		t.Errorf("expected pkg.go.dev license from mocked response, got %+v", other)
+	}
+}
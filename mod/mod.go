@@ -1,14 +1,47 @@
 package mod
 
 import (
+	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"golang.org/x/mod/modfile"
 	"golang.org/x/mod/module"
 )
 
-const goMod = "go.mod"
+const (
+	goMod  = "go.mod"
+	goWork = "go.work"
+	goSum  = "go.sum"
+)
+
+// Option configures parsing of go.mod and go.work files
+type Option func(*options)
+
+type options struct {
+	logger *slog.Logger
+}
+
+// WithLogger makes parsing log skipped dependencies to l instead of slog.Default()
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{logger: slog.Default()}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.logger == nil {
+		o.logger = slog.Default()
+	}
+	return o
+}
 
 func Exists(path string) bool {
 	if _, err := os.Stat(filepath.Join(path, goMod)); err == nil || os.IsExist(err) {
@@ -17,7 +50,174 @@ func Exists(path string) bool {
 	return false
 }
 
-func Parse(path string, withIndirect bool) ([]module.Version, error) {
+// ExistsWork reports whether path contains go.work file
+func ExistsWork(path string) bool {
+	if _, err := os.Stat(filepath.Join(path, goWork)); err == nil || os.IsExist(err) {
+		return true
+	}
+	return false
+}
+
+// HasModule reports whether path contains go.mod and go.work files
+func HasModule(path string) (hasGoMod, hasGoWork bool) {
+	return Exists(path), ExistsWork(path)
+}
+
+// ModulePath returns module path declared in go.mod at path
+func ModulePath(path string) (string, error) {
+	bts, err := os.ReadFile(filepath.Join(path, goMod))
+	if err != nil {
+		return "", err
+	}
+
+	return modfile.ModulePath(bts), nil
+}
+
+// ModuleInfo returns module path, Go version and license of go.mod at path. License is taken from
+// "// License: <SPDX-ID>" comment anywhere in go.mod, and is empty when there is no such comment.
+func ModuleInfo(path string) (modulePath, goVersion, license string, err error) {
+	bts, err := os.ReadFile(filepath.Join(path, goMod))
+	if err != nil {
+		return "", "", "", err
+	}
+
+	modFile, err := modfile.Parse(goMod, bts, nil)
+	if err != nil {
+		return "", "", "", err
+	}
+	if modFile.Module != nil {
+		modulePath = modFile.Module.Mod.Path
+	}
+	if modFile.Go != nil {
+		goVersion = modFile.Go.Version
+	}
+	return modulePath, goVersion, licenseComment(modFile.Syntax), nil
+}
+
+const licenseCommentPrefix = "license:"
+
+// licenseComment returns SPDX identifier of the first License comment in f
+func licenseComment(f *modfile.FileSyntax) string {
+	var comments []modfile.Comment
+	add := func(c *modfile.Comments) {
+		comments = append(comments, c.Before...)
+		comments = append(comments, c.Suffix...)
+		comments = append(comments, c.After...)
+	}
+	add(f.Comment())
+	for _, stmt := range f.Stmt {
+		add(stmt.Comment())
+		if block, ok := stmt.(*modfile.LineBlock); ok {
+			for _, l := range block.Line {
+				add(l.Comment())
+			}
+		}
+	}
+
+	for _, c := range comments {
+		text := strings.TrimSpace(strings.TrimPrefix(c.Token, "//"))
+		if len(text) < len(licenseCommentPrefix) || !strings.EqualFold(text[:len(licenseCommentPrefix)], licenseCommentPrefix) {
+			continue
+		}
+		if fields := strings.Fields(text[len(licenseCommentPrefix):]); len(fields) > 0 {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
+// ToolchainVersion returns toolchain declared by toolchain directive in go.mod at path (e.g. go1.21.3),
+// or empty string if go.mod doesn't have one
+func ToolchainVersion(path string) (string, error) {
+	bts, err := os.ReadFile(filepath.Join(path, goMod))
+	if err != nil {
+		return "", err
+	}
+
+	modFile, err := modfile.Parse(goMod, bts, nil)
+	if err != nil {
+		return "", err
+	}
+	if modFile.Toolchain == nil {
+		return "", nil
+	}
+	return modFile.Toolchain.Name, nil
+}
+
+// RequireLines returns line in go.mod at path on which each dependency is required. Replacements
+// are mapped to the line of their replace directive.
+func RequireLines(path string) (map[string]int, error) {
+	bts, err := os.ReadFile(filepath.Join(path, goMod))
+	if err != nil {
+		return nil, err
+	}
+
+	modFile, err := modfile.Parse(goMod, bts, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := map[string]int{}
+	for _, r := range modFile.Require {
+		lines[r.Mod.Path] = r.Syntax.Start.Line
+	}
+	for _, r := range modFile.Replace {
+		if _, ok := lines[r.New.Path]; !ok {
+			lines[r.New.Path] = r.Syntax.Start.Line
+		}
+	}
+	return lines, nil
+}
+
+// ReplacedPaths returns path of module each replacement in go.mod at path replaces, keyed by path
+// of the replacement. Replacements with local directories are left out.
+func ReplacedPaths(path string) (map[string]string, error) {
+	bts, err := os.ReadFile(filepath.Join(path, goMod))
+	if err != nil {
+		return nil, err
+	}
+
+	modFile, err := modfile.Parse(goMod, bts, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := map[string]string{}
+	for _, r := range modFile.Replace {
+		if _, ok := paths[r.New.Path]; !ok && r.New.Version != "" {
+			paths[r.New.Path] = r.Old.Path
+		}
+	}
+	return paths, nil
+}
+
+// ParseGoSum parses go.sum at path and returns h1: hashes of module contents keyed by "module@version".
+// Hashes of go.mod files ("module@version/go.mod" lines) are skipped.
+func ParseGoSum(path string) (map[string]string, error) {
+	bts, err := os.ReadFile(filepath.Join(path, goSum))
+	if err != nil {
+		return nil, err
+	}
+
+	sums := map[string]string{}
+	for i, line := range strings.Split(string(bts), "\n") {
+		f := strings.Fields(line)
+		if len(f) == 0 {
+			continue
+		}
+		if len(f) != 3 {
+			return nil, fmt.Errorf("%s:%d: malformed line %q", goSum, i+1, line)
+		}
+		if strings.HasSuffix(f[1], "/go.mod") || !strings.HasPrefix(f[2], "h1:") {
+			continue
+		}
+		sums[f[0]+"@"+f[1]] = f[2]
+	}
+	return sums, nil
+}
+
+func Parse(path string, withIndirect bool, opts ...Option) ([]module.Version, error) {
+	o := newOptions(opts)
 	bts, err := os.ReadFile(filepath.Join(path, goMod))
 	if err != nil {
 		return nil, err
@@ -33,8 +233,170 @@ func Parse(path string, withIndirect bool) ([]module.Version, error) {
 		if f.Indirect && !withIndirect {
 			continue
 		}
+		if excluded(f.Mod, modFile.Exclude) {
+			o.logger.Warn("Skipping dependency that is both required and excluded, go.mod may be malformed", "dependency", f.Mod.Path, "version", f.Mod.Version)
+			continue
+		}
 		deps = append(deps, f.Mod)
 	}
 
-	return deps, nil
+	return replace(deps, modFile.Replace, o.logger), nil
+}
+
+// excluded reports whether d is excluded by one of exclude directives
+func excluded(d module.Version, excludes []*modfile.Exclude) bool {
+	for _, e := range excludes {
+		if e.Mod == d {
+			return true
+		}
+	}
+	return false
+}
+
+// replace substitutes dependencies with their replacements, so that e.g. forks are reported
+// instead of the original modules. Dependencies replaced with local directories are omitted.
+func replace(deps []module.Version, replaces []*modfile.Replace, logger *slog.Logger) []module.Version {
+	if len(replaces) < 1 {
+		return deps
+	}
+
+	var replaced []module.Version
+	for _, d := range deps {
+		r := findReplace(d, replaces)
+		if r == nil {
+			replaced = append(replaced, d)
+			continue
+		}
+		if r.New.Version == "" {
+			logger.Info("Skipping dependency replaced by local directory", "dependency", d.Path, "directory", r.New.Path)
+			continue
+		}
+		replaced = append(replaced, r.New)
+	}
+	return replaced
+}
+
+// findReplace returns replace directive for dependency. Replacements of a specific
+// version take precedence over the ones for all versions of a module.
+func findReplace(d module.Version, replaces []*modfile.Replace) *modfile.Replace {
+	var found *modfile.Replace
+	for _, r := range replaces {
+		if r.Old.Path != d.Path {
+			continue
+		}
+		if r.Old.Version == d.Version {
+			return r
+		}
+		if r.Old.Version == "" {
+			found = r
+		}
+	}
+	return found
+}
+
+// ParseWork parses go.work at path and returns dependencies of all modules it uses.
+// Dependencies are deduplicated by path and version, and modules that are part of
+// the workspace are not returned as dependencies of each other.
+func ParseWork(path string, withIndirect bool, opts ...Option) ([]module.Version, error) {
+	o := newOptions(opts)
+	bts, err := os.ReadFile(filepath.Join(path, goWork))
+	if err != nil {
+		return nil, err
+	}
+
+	workFile, err := modfile.ParseWork(goWork, bts, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace := map[string]bool{}
+	var deps []module.Version
+	for _, u := range workFile.Use {
+		dir := filepath.Join(path, filepath.FromSlash(u.Path))
+		modPath, err := ModulePath(dir)
+		if err != nil {
+			return nil, err
+		}
+		workspace[modPath] = true
+
+		modDeps, err := Parse(dir, withIndirect, opts...)
+		if err != nil {
+			return nil, err
+		}
+		deps = append(deps, modDeps...)
+	}
+	deps = replace(deps, workFile.Replace, o.logger)
+
+	seen := map[module.Version]bool{}
+	var unique []module.Version
+	for _, d := range deps {
+		if seen[d] || workspace[d.Path] {
+			continue
+		}
+		seen[d] = true
+		unique = append(unique, d)
+	}
+
+	return unique, nil
+}
+
+// FindAll walks directory tree rooted at root and returns all directories containing go.mod,
+// in lexical order. Like go command, it skips vendor and testdata directories, and directories
+// whose names start with a dot or underscore.
+func FindAll(root string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if name := d.Name(); path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+		if Exists(path) {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	return dirs, err
+}
+
+// ParseAll parses all go.mod files found by FindAll in directory tree rooted at root and returns
+// their dependencies. Like with ParseWork, dependencies are deduplicated by path and version, and
+// modules found in the tree are not returned as dependencies of each other.
+func ParseAll(root string, withIndirect bool, opts ...Option) ([]module.Version, error) {
+	dirs, err := FindAll(root)
+	if err != nil {
+		return nil, err
+	}
+
+	local := map[string]bool{}
+	var deps []module.Version
+	for _, dir := range dirs {
+		modPath, err := ModulePath(dir)
+		if err != nil {
+			return nil, err
+		}
+		local[modPath] = true
+
+		modDeps, err := Parse(dir, withIndirect, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", dir, err)
+		}
+		deps = append(deps, modDeps...)
+	}
+
+	seen := map[module.Version]bool{}
+	var unique []module.Version
+	for _, d := range deps {
+		if seen[d] || local[d.Path] {
+			continue
+		}
+		seen[d] = true
+		unique = append(unique, d)
+	}
+
+	return unique, nil
 }
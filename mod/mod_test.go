@@ -0,0 +1,370 @@
+package mod
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/mod/module"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestParseWork(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"go.work": "go 1.18\n\nuse (\n\t./a\n\t./b\n)\n",
+		"a/go.mod": `module example.com/a
+
+go 1.18
+
+require (
+	example.com/b v0.0.0
+	github.com/fatih/color v1.17.0
+	golang.org/x/mod v0.20.0 // indirect
+)
+`,
+		"b/go.mod": `module example.com/b
+
+go 1.18
+
+require github.com/fatih/color v1.17.0
+`,
+	})
+
+	if !ExistsWork(dir) || ExistsWork(filepath.Join(dir, "a")) {
+		t.Error("ExistsWork() reported wrong go.work presence")
+	}
+
+	got, err := ParseWork(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []module.Version{{Path: "github.com/fatih/color", Version: "v1.17.0"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseWork() = %v, want %v", got, want)
+	}
+
+	got, err = ParseWork(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = append(want, module.Version{Path: "golang.org/x/mod", Version: "v0.20.0"})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseWork() = %v, want %v", got, want)
+	}
+
+	if _, err := ParseWork(filepath.Join(dir, "a"), false); err == nil {
+		t.Error("expected error for missing go.work")
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"go.mod":                  "module example.com/root\n\ngo 1.21\n\nrequire github.com/fatih/color v1.16.0\n",
+		"svc/a/go.mod":            "module example.com/a\n\ngo 1.21\n\nrequire (\n\texample.com/root v0.0.0\n\tgithub.com/fatih/color v1.17.0\n)\n",
+		"svc/b/go.mod":            "module example.com/b\n\ngo 1.21\n\nrequire github.com/fatih/color v1.17.0\n",
+		"svc/empty/main.go":       "package main\n",
+		"vendor/x/go.mod":         "module example.com/x\n",
+		"svc/a/testdata/go.mod":   "module example.com/testdata\n",
+		".git/go.mod":             "module example.com/git\n",
+		"_examples/ex/go.mod":     "module example.com/ex\n",
+		"svc/b/internal/c/go.mod": "module example.com/c\n\ngo 1.21\n\nrequire golang.org/x/mod v0.20.0\n",
+	})
+
+	got, err := FindAll(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{dir, filepath.Join(dir, "svc", "a"), filepath.Join(dir, "svc", "b"), filepath.Join(dir, "svc", "b", "internal", "c")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll() = %v, want %v", got, want)
+	}
+
+	deps, err := ParseAll(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDeps := []module.Version{
+		{Path: "github.com/fatih/color", Version: "v1.16.0"},
+		{Path: "github.com/fatih/color", Version: "v1.17.0"},
+		{Path: "golang.org/x/mod", Version: "v0.20.0"},
+	}
+	if !reflect.DeepEqual(deps, wantDeps) {
+		t.Errorf("ParseAll() = %v, want %v", deps, wantDeps)
+	}
+
+	if _, err := FindAll(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected error for missing root")
+	}
+}
+
+func TestParse_Replace(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"go.mod": `module example.com/a
+
+go 1.18
+
+require (
+	github.com/fatih/color v1.17.0
+	github.com/olekukonko/tablewriter v0.0.5
+	golang.org/x/mod v0.20.0
+	golang.org/x/oauth2 v0.22.0
+)
+
+replace github.com/fatih/color => github.com/ribice/color v1.18.0
+
+replace golang.org/x/mod v0.19.0 => golang.org/x/mod v0.1.0
+
+replace golang.org/x/oauth2 v0.22.0 => github.com/ribice/oauth2 v0.23.0
+
+replace github.com/olekukonko/tablewriter => ./tablewriter
+`,
+	})
+
+	logs := &bytes.Buffer{}
+	got, err := Parse(dir, false, WithLogger(slog.New(slog.NewTextHandler(logs, nil))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := logs.String(); !strings.Contains(l, "dependency=github.com/olekukonko/tablewriter directory=./tablewriter") {
+		t.Errorf("expected local replacement to be logged with configured logger, got %q", l)
+	}
+	want := []module.Version{
+		{Path: "github.com/ribice/color", Version: "v1.18.0"},
+		{Path: "golang.org/x/mod", Version: "v0.20.0"},
+		{Path: "github.com/ribice/oauth2", Version: "v0.23.0"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %v, want %v", got, want)
+	}
+}
+
+func TestParse_Exclude(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"go.mod": `module example.com/a
+
+go 1.18
+
+require (
+	github.com/fatih/color v1.17.0
+	golang.org/x/text v0.1.0
+)
+
+exclude (
+	golang.org/x/text v0.1.0
+	github.com/fatih/color v1.16.0
+)
+`,
+	})
+
+	got, err := Parse(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []module.Version{{Path: "github.com/fatih/color", Version: "v1.17.0"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %v, want %v", got, want)
+	}
+}
+
+func TestRequireLines(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"go.mod": `module example.com/a
+
+go 1.18
+
+require github.com/fatih/color v1.17.0
+
+require (
+	github.com/ribice/kiss v1.0.0
+	golang.org/x/mod v0.20.0 // indirect
+)
+
+replace github.com/ribice/kiss => github.com/fork/kiss v1.0.1
+`,
+	})
+
+	got, err := RequireLines(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{
+		"github.com/fatih/color": 5,
+		"github.com/ribice/kiss": 8,
+		"golang.org/x/mod":       9,
+		"github.com/fork/kiss":   12,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RequireLines() = %v, want %v", got, want)
+	}
+
+	if _, err := RequireLines(t.TempDir()); err == nil {
+		t.Error("expected error for missing go.mod")
+	}
+}
+
+func TestReplacedPaths(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"go.mod": `module example.com/a
+
+go 1.18
+
+require (
+	github.com/ribice/kiss v1.0.0
+	github.com/some/local v1.0.0
+)
+
+replace github.com/ribice/kiss => github.com/fork/kiss v1.0.1
+
+replace github.com/some/local => ../local
+`,
+	})
+
+	got, err := ReplacedPaths(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"github.com/fork/kiss": "github.com/ribice/kiss"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReplacedPaths() = %v, want %v", got, want)
+	}
+
+	if _, err := ReplacedPaths(t.TempDir()); err == nil {
+		t.Error("expected error for missing go.mod")
+	}
+}
+
+func TestToolchainVersion(t *testing.T) {
+	tests := map[string]struct {
+		gomod   string
+		want    string
+		wantErr bool
+	}{
+		"toolchain":    {gomod: "module example.com/a\n\ngo 1.21\n\ntoolchain go1.21.3\n", want: "go1.21.3"},
+		"no toolchain": {gomod: "module example.com/a\n\ngo 1.21\n"},
+		"invalid":      {gomod: "module example.com/a\n\ntoolchain\n", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := writeFiles(t, map[string]string{"go.mod": tt.gomod})
+			got, err := ToolchainVersion(dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToolchainVersion() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ToolchainVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if _, err := ToolchainVersion(t.TempDir()); err == nil {
+		t.Error("expected error without go.mod")
+	}
+}
+
+func TestModuleInfo(t *testing.T) {
+	tests := map[string]struct {
+		gomod       string
+		wantPath    string
+		wantGo      string
+		wantLicense string
+		wantErr     bool
+	}{
+		"license before module": {
+			gomod:    "// License: Apache-2.0\nmodule example.com/a\n\ngo 1.21\n",
+			wantPath: "example.com/a", wantGo: "1.21", wantLicense: "Apache-2.0",
+		},
+		"license suffix": {
+			gomod:    "module example.com/a // license: MIT\n\ngo 1.22.1\n",
+			wantPath: "example.com/a", wantGo: "1.22.1", wantLicense: "MIT",
+		},
+		"license in require block": {
+			gomod:    "module example.com/a\n\ngo 1.21\n\nrequire (\n\t// License: BSD-3-Clause\n\texample.com/b v1.0.0\n)\n",
+			wantPath: "example.com/a", wantGo: "1.21", wantLicense: "BSD-3-Clause",
+		},
+		"no license": {
+			gomod:    "// Licensed to nobody\nmodule example.com/a\n\ngo 1.21\n",
+			wantPath: "example.com/a", wantGo: "1.21",
+		},
+		"invalid": {gomod: "module example.com/a\n\ngo\n", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := writeFiles(t, map[string]string{"go.mod": tt.gomod})
+			path, goVersion, license, err := ModuleInfo(dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ModuleInfo() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if path != tt.wantPath || goVersion != tt.wantGo || license != tt.wantLicense {
+				t.Errorf("ModuleInfo() = %q, %q, %q, want %q, %q, %q", path, goVersion, license, tt.wantPath, tt.wantGo, tt.wantLicense)
+			}
+		})
+	}
+	if _, _, _, err := ModuleInfo(t.TempDir()); err == nil {
+		t.Error("expected error without go.mod")
+	}
+}
+
+func TestParseGoSum(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"go.sum": `github.com/fatih/color v1.17.0 h1:GlRw1BRJxkpqUCBKzKOw098ed57fEsKeNjpTe3cSjK4=
+github.com/fatih/color v1.17.0/go.mod h1:YZ7TlrGPkiz6ku9fK3TLD/pl3CpsiFyu8N92HLgmosI=
+golang.org/x/mod v0.20.0/go.mod h1:hTbmBsO62+eylJbnUtE2MGJUyE7QWk4xUqPFrRgJ+7c=
+
+`,
+	})
+
+	got, err := ParseGoSum(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"github.com/fatih/color@v1.17.0": "h1:GlRw1BRJxkpqUCBKzKOw098ed57fEsKeNjpTe3cSjK4="}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseGoSum() = %v, want %v", got, want)
+	}
+
+	malformed := writeFiles(t, map[string]string{"go.sum": "github.com/fatih/color v1.17.0\n"})
+	if _, err := ParseGoSum(malformed); err == nil {
+		t.Error("expected error for malformed go.sum")
+	}
+}
+
+func TestHasModule(t *testing.T) {
+	tests := map[string]struct {
+		files             []string
+		wantMod, wantWork bool
+	}{
+		"empty":     {},
+		"go.mod":    {files: []string{"go.mod"}, wantMod: true},
+		"go.work":   {files: []string{"go.work"}, wantWork: true},
+		"workspace": {files: []string{"go.mod", "go.work"}, wantMod: true, wantWork: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, f := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, f), []byte("go 1.18\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if gotMod, gotWork := HasModule(dir); gotMod != tt.wantMod || gotWork != tt.wantWork {
+				t.Errorf("HasModule() = %t, %t, want %t, %t", gotMod, gotWork, tt.wantMod, tt.wantWork)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package mod
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+)
+
+const defaultGoProxy = "https://proxy.golang.org"
+
+// ErrNoProxy is returned when GOPROXY doesn't contain any module proxy, e.g. GOPROXY=direct
+var ErrNoProxy = errors.New("no module proxy in GOPROXY")
+
+// proxyClient is HTTP client used for module proxy requests
+var proxyClient = &http.Client{Timeout: 10 * time.Second}
+
+// Info is module metadata served by module proxy at $GOPROXY/<module>/@v/<version>.info
+type Info struct {
+	Version string
+	Time    time.Time
+	// Origin is set by proxies running Go 1.19 or newer
+	Origin *Origin
+}
+
+// Origin describes repository module version was downloaded from
+type Origin struct {
+	VCS  string
+	URL  string
+	Ref  string
+	Hash string
+}
+
+// ResolveViaProxy fetches canonical version of module modPath at version (e.g. a branch name or
+// pseudo-version) from module proxies set in GOPROXY environment variable. Empty version resolves
+// latest version.
+func ResolveViaProxy(modPath, version string) (*module.Version, error) {
+	info, err := ProxyInfo(modPath, version)
+	if err != nil {
+		return nil, err
+	}
+	return &module.Version{Path: modPath, Version: info.Version}, nil
+}
+
+// ProxyInfo fetches metadata of module modPath at version from module proxies set in GOPROXY
+// environment variable, trying them in order. Empty version fetches latest version.
+func ProxyInfo(modPath, version string) (*Info, error) {
+	proxies := goProxies(os.Getenv("GOPROXY"))
+	if len(proxies) < 1 {
+		return nil, ErrNoProxy
+	}
+
+	escPath, err := module.EscapePath(modPath)
+	if err != nil {
+		return nil, err
+	}
+	suffix := "/@latest"
+	if version != "" {
+		escVersion, err := module.EscapeVersion(version)
+		if err != nil {
+			return nil, err
+		}
+		suffix = "/@v/" + escVersion + ".info"
+	}
+
+	var errs []error
+	for _, p := range proxies {
+		info, err := fetchInfo(p + "/" + escPath + suffix)
+		if err == nil {
+			return info, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+// goProxies returns URLs of module proxies in GOPROXY value, skipping direct and off
+func goProxies(env string) []string {
+	if env == "" {
+		env = defaultGoProxy
+	}
+	var proxies []string
+	for _, p := range strings.FieldsFunc(env, func(r rune) bool { return r == ',' || r == '|' }) {
+		p = strings.TrimSpace(p)
+		if p == "" || p == "direct" || p == "off" {
+			continue
+		}
+		proxies = append(proxies, strings.TrimSuffix(p, "/"))
+	}
+	return proxies
+}
+
+func fetchInfo(url string) (*Info, error) {
+	resp, err := proxyClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+
+	var info Info
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("%s: %w", url, err)
+	}
+	if info.Version == "" {
+		return nil, fmt.Errorf("%s: missing version", url)
+	}
+	return &info, nil
+}
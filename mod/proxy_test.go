@@ -0,0 +1,69 @@
+package mod
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGoProxies(t *testing.T) {
+	tests := map[string][]string{
+		"":                                   {defaultGoProxy},
+		"direct":                             nil,
+		"off":                                nil,
+		"https://proxy.company.com/,direct":  {"https://proxy.company.com"},
+		"https://a.com|https://b.com,direct": {"https://a.com", "https://b.com"},
+	}
+	for env, want := range tests {
+		if got := goProxies(env); !reflect.DeepEqual(got, want) {
+			t.Errorf("goProxies(%q) = %v, want %v", env, got, want)
+		}
+	}
+}
+
+func TestResolveViaProxy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		// uppercase letters are escaped as ! followed by lowercase letter
+		case "/company.com/!private/lib/@v/master.info":
+			fmt.Fprint(w, `{"Version": "v0.0.0-20240102030405-abcdefabcdef", "Time": "2024-01-02T03:04:05Z", "Origin": {"VCS": "git", "URL": "https://git.company.com/team/lib"}}`)
+		case "/company.com/!private/lib/@latest":
+			fmt.Fprint(w, `{"Version": "v1.2.0"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("GOPROXY", "https://127.0.0.1:0,"+srv.URL+",direct")
+
+	v, err := ResolveViaProxy("company.com/Private/lib", "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Path != "company.com/Private/lib" || v.Version != "v0.0.0-20240102030405-abcdefabcdef" {
+		t.Errorf("ResolveViaProxy() = %v, want canonical pseudo-version", v)
+	}
+
+	info, err := ProxyInfo("company.com/Private/lib", "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Origin == nil || info.Origin.URL != "https://git.company.com/team/lib" {
+		t.Errorf("ProxyInfo() origin = %+v, want repository URL", info.Origin)
+	}
+
+	if v, err := ResolveViaProxy("company.com/Private/lib", ""); err != nil || v.Version != "v1.2.0" {
+		t.Errorf("ResolveViaProxy() = %v, %v, want latest version", v, err)
+	}
+	if _, err := ResolveViaProxy("company.com/missing", "v1.0.0"); err == nil {
+		t.Error("expected error for module unknown to proxies")
+	}
+
+	t.Setenv("GOPROXY", "direct")
+	if _, err := ResolveViaProxy("company.com/Private/lib", "master"); !errors.Is(err, ErrNoProxy) {
+		t.Errorf("expected ErrNoProxy, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package glice
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultNoticeFileName = "AGGREGATE-NOTICES.txt"
+	noticeSeparator       = "--------------------------------------------------------------------------------"
+)
+
+// WriteNoticeFile writes license texts of all dependencies into a single file in dest directory,
+// as required by attribution obligations of licenses such as Apache-2.0. When go.mod declares
+// license of the module itself (see Client.ModuleInfo), it's written at the top of the file.
+// Dependencies without license text are skipped.
+func (c *Client) WriteNoticeFile(dest string) error {
+	name := c.noticeFileName
+	if name == "" {
+		name = defaultNoticeFileName
+	}
+
+	f, err := os.Create(filepath.Join(dest, name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	bw := bufio.NewWriter(f)
+	w := &errWriter{w: bw}
+	if name, _, license, err := c.ModuleInfo(); err == nil && license != "" {
+		w.printf("=== %s ===\n\n", name)
+		w.printf("Licensed under %s.\n\n", license)
+		w.printf("%s\n\n", noticeSeparator)
+	}
+	for _, d := range c.dependencies {
+		if d.Text == "" {
+			continue
+		}
+		text, err := d.LicenseText()
+		if err != nil {
+			return err
+		}
+		w.printf("=== %s %s ===\n\n", d.Name, d.Version)
+		w.printf("%s\n\n", strings.TrimSpace(text))
+		w.printf("%s\n\n", noticeSeparator)
+	}
+	if w.err != nil {
+		return w.err
+	}
+
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return f.Close()
+}
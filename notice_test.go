@@ -0,0 +1,63 @@
+package glice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClient_WriteNoticeFile(t *testing.T) {
+	c := &Client{dependencies: []*Repository{
+		{Name: "github.com/ribice/glice", Version: "v1.0.0", Text: "bGljZW5zZS10ZXh0"},
+		{Name: "golang.org/x/mod", Version: "v0.20.0"},
+		{Name: "example.com/plain", Version: "v0.1.0", Text: "Plain license text\n"},
+	}}
+
+	dir := t.TempDir()
+	if err := c.WriteNoticeFile(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, defaultNoticeFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "=== github.com/ribice/glice v1.0.0 ===\n\nlicense-text\n\n" + noticeSeparator + "\n\n" +
+		"=== example.com/plain v0.1.0 ===\n\nPlain license text\n\n" + noticeSeparator + "\n\n"
+	if string(got) != want {
+		t.Errorf("WriteNoticeFile() wrote %q, want %q", got, want)
+	}
+
+	c.noticeFileName = "NOTICE"
+	if err := c.WriteNoticeFile(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "NOTICE")); err != nil {
+		t.Error(err)
+	}
+
+	if err := c.WriteNoticeFile(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
+
+func TestClient_WriteNoticeFileModuleLicense(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("// License: Apache-2.0\nmodule example.com/a\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{path: dir, dependencies: []*Repository{{Name: "example.com/plain", Version: "v0.1.0", Text: "Plain license text\n"}}}
+	if err := c.WriteNoticeFile(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, defaultNoticeFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "=== example.com/a ===\n\nLicensed under Apache-2.0.\n\n" + noticeSeparator + "\n\n" +
+		"=== example.com/plain v0.1.0 ===\n\nPlain license text\n\n" + noticeSeparator + "\n\n"
+	if string(got) != want {
+		t.Errorf("WriteNoticeFile() wrote %q, want %q", got, want)
+	}
+}
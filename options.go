@@ -0,0 +1,445 @@
+package glice
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures optional Client settings
+type Option func(*Client)
+
+// WithFormat sets output format, defaults to table
+func WithFormat(format string) Option {
+	return func(c *Client) {
+		c.format = format
+	}
+}
+
+// WithOutput sets output location, defaults to stdout
+func WithOutput(output string) Option {
+	return func(c *Client) {
+		c.output = output
+		c.outputs = nil
+	}
+}
+
+// WithOutputs sets multiple outputs Write prints to at once, e.g. both stdout and file
+func WithOutputs(outputs ...string) Option {
+	return func(c *Client) {
+		c.outputs = outputs
+	}
+}
+
+// WithOutputWriter sets writer Write prints to, in addition to outputs
+func WithOutputWriter(w io.Writer) Option {
+	return func(c *Client) {
+		c.outputWriter = w
+	}
+}
+
+// WithOutputFile sets path of the file written with file and both outputs
+func WithOutputFile(path string) Option {
+	return func(c *Client) {
+		c.outputFile = path
+	}
+}
+
+// WithConcurrency sets how many licenses are fetched at the same time, defaults to 5.
+// Higher values speed up large dependency sets, but note that unauthenticated GitHub
+// API is limited to 60 requests per hour regardless of concurrency. Use 1 when API
+// rate limits are strict. Values lower than 1 are rejected by NewClient.
+func WithConcurrency(n int) Option {
+	return func(c *Client) {
+		c.concurrency = n
+	}
+}
+
+// WithTimeout sets timeout of each API request and pkg.go.dev scrape, defaults to 10 seconds
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithLogger sets structured logger used while parsing dependencies, defaults to slog.Default,
+// which writes through the standard logger
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithHTTPClient sets HTTP client used for API requests and its transport for pages scraped from pkg.go.dev,
+// e.g. to go through a corporate proxy or to return canned responses in tests
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithAPIKey sets API key for host (e.g. github.com), overriding the one from environment variables.
+// For bitbucket.org the key is "username:app-password" pair.
+func WithAPIKey(host, key string) Option {
+	return func(c *Client) {
+		c.apiKeys[host] = key
+	}
+}
+
+// WithTemplate sets text/template used to render dependencies in template format.
+// Template is executed against []*Repository and defaults to DefaultTemplate.
+func WithTemplate(tmpl string) Option {
+	return func(c *Client) {
+		c.templateText = tmpl
+	}
+}
+
+// WithLicenseOutputDir sets directory WriteLicensesToFile writes license files to,
+// defaults to licenses directory in scanned path
+func WithLicenseOutputDir(dir string) Option {
+	return func(c *Client) {
+		c.licenseDir = dir
+	}
+}
+
+// WithLicenseFileTemplate sets text/template of license file names written by WriteLicensesToFile,
+// executed against each dependency. Defaults to "{{.Author}}-{{.Project}}-license.MD", other
+// example is "{{.Project}}-{{.LicenseSPDX}}.txt".
+func WithLicenseFileTemplate(tmpl string) Option {
+	return func(c *Client) {
+		c.licenseFileTmplText = tmpl
+	}
+}
+
+// WithCache stores fetched licenses in dir, skipping network calls for ones cached less than ttl ago.
+// Zero ttl means cached licenses never expire.
+func WithCache(dir string, ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cache = &diskCache{dir: dir, ttl: ttl, now: time.Now}
+	}
+}
+
+// WithCompatibilityRules adds rules used by CompatibilityMatrix, taking precedence over built-in ones.
+// Rules map license to licenses it is (in)compatible with, e.g. {"MIT": {"SSPL-1.0": false}}.
+// They apply in both directions and licenses are compared case-insensitively, with -only forms compared
+// as their base license. -or-later forms without own rule use rules of versions they can be used under.
+func WithCompatibilityRules(rules map[string]map[string]bool) Option {
+	return func(c *Client) {
+		if c.compatRules == nil {
+			c.compatRules = map[string]map[string]bool{}
+		}
+		for a, bs := range rules {
+			a = compatKey(a)
+			if c.compatRules[a] == nil {
+				c.compatRules[a] = map[string]bool{}
+			}
+			for b, ok := range bs {
+				c.compatRules[a][compatKey(b)] = ok
+			}
+		}
+	}
+}
+
+// WithWebhook posts JSON payload to url once ParseDependencies detects events, e.g. Slack incoming webhook.
+// The only event is "violation", sent when dependencies use license from BlockedLicenses or unknown license,
+// and it is used when no events are given. Request is limited by timeout set with WithTimeout.
+func WithWebhook(url string, events ...string) Option {
+	return func(c *Client) {
+		if len(events) < 1 {
+			events = []string{"violation"}
+		}
+		c.webhookURL = url
+		c.webhookEvents = events
+	}
+}
+
+// WithServeTTL sets how long Serve reuses parsed dependencies before parsing them again, defaults to 5 minutes
+func WithServeTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.serveTTL = ttl
+	}
+}
+
+// WithRetry retries API requests failed with network errors, 429 or 5xx responses up to maxAttempts times in total.
+// Wait between attempts starts at base and doubles on each retry, capped at 30 seconds.
+// 429 Too Many Requests responses wait as long as their Retry-After header says.
+func WithRetry(maxAttempts int, base time.Duration) Option {
+	return func(c *Client) {
+		c.retry = retryPolicy{maxAttempts: maxAttempts, base: base}
+	}
+}
+
+// WithCircuitBreaker stops fetching licenses from a host (e.g. pkg.go.dev) after failureThreshold
+// consecutive requests failed with network errors, 429 or 5xx responses, so a down host doesn't make
+// every remaining dependency wait for timeout. Remaining dependencies of the host get UNREACHABLE
+// license and ErrHostUnreachable in Client.Errors.
+// After openDuration, single request is sent to the host again, resuming fetching if it succeeds.
+func WithCircuitBreaker(failureThreshold int, openDuration time.Duration) Option {
+	return func(c *Client) {
+		c.breakerThreshold = failureThreshold
+		c.breakerOpen = openDuration
+	}
+}
+
+// WithWorkspace makes ParseDependencies parse all modules of go.work when it's present at path
+func WithWorkspace() Option {
+	return func(c *Client) {
+		c.workspace = true
+	}
+}
+
+// WithIgnore skips dependencies matching any of the patterns, so their licenses are not fetched.
+// Patterns are either exact module paths or path.Match globs, e.g. "golang.org/x/*".
+func WithIgnore(patterns ...string) Option {
+	return func(c *Client) {
+		c.ignore = append(c.ignore, patterns...)
+	}
+}
+
+// WithNoticeFileName sets name of the file written by WriteNoticeFile, defaults to AGGREGATE-NOTICES.txt
+func WithNoticeFileName(name string) Option {
+	return func(c *Client) {
+		c.noticeFileName = name
+	}
+}
+
+// WithColumns sets columns shown in table format, defaults to dependency, url, license and version.
+// Other available columns are host, author, category, risk (or risk_score), stars, sha and vulns.
+// Names are case insensitive and NewClient rejects unknown ones.
+func WithColumns(cols ...string) Option {
+	return func(c *Client) {
+		c.columns = make([]string, len(cols))
+		for i, col := range cols {
+			c.columns[i] = strings.ToLower(strings.TrimSpace(col))
+		}
+	}
+}
+
+// WithFailOnCopyleft makes ParseDependencies return ErrCopyleftFound when any dependency
+// uses strong or network copyleft license (e.g. GPL-3.0, AGPL-3.0)
+func WithFailOnCopyleft() Option {
+	return func(c *Client) {
+		c.failOnCopyleft = true
+	}
+}
+
+// WithFailOnUnknown makes ParseDependencies return ErrUnknownLicense when any dependency has no license
+// or one reported as "Other". Use WithIgnore or LicenseOverrides for dependencies known to be fine.
+func WithFailOnUnknown() Option {
+	return func(c *Client) {
+		c.failOnUnknown = true
+	}
+}
+
+// WithGitHubBaseURL sets API URL of GitHub Enterprise Server (e.g. https://github.example.com/api/v3/)
+// used to fetch licenses of github.com dependencies. Defaults to GITHUB_API_URL env variable.
+func WithGitHubBaseURL(u string) Option {
+	return func(c *Client) {
+		c.githubBaseURL = u
+	}
+}
+
+// WithLegacyJSON prints json format as array of dependencies, as glice did before it was printed as object
+// with modules and summary keys. With WithSummary, the array is printed under dependencies key instead.
+func WithLegacyJSON() Option {
+	return func(c *Client) {
+		c.legacyJSON = true
+	}
+}
+
+// WithPreferredSPDX selects license of dual licensed dependencies (e.g. MIT OR Apache-2.0). When spdxID
+// isn't one of their licenses, the least restrictive one is selected, see LicenseRiskScore.
+func WithPreferredSPDX(spdxID string) Option {
+	return func(c *Client) {
+		c.preferredSPDX = spdxID
+	}
+}
+
+// WithIncludeToolchain adds Go toolchain declared by go.mod toolchain directive to dependencies as
+// golang.org/toolchain module, so that license reports include license of Go itself
+func WithIncludeToolchain() Option {
+	return func(c *Client) {
+		c.includeToolchain = true
+	}
+}
+
+// WithIndirect makes ParseDependencies and ParseWorkspaceDependencies include indirect dependencies,
+// regardless of their includeIndirect argument
+func WithIndirect() Option {
+	return func(c *Client) {
+		c.indirect = true
+	}
+}
+
+// WithPageSize makes table format printed to terminal stop after every n rows, until Enter is pressed.
+// Paging is disabled when output isn't a terminal, e.g. when it's piped or written to file.
+func WithPageSize(n int) Option {
+	return func(c *Client) {
+		c.pageSize = n
+	}
+}
+
+// WithDryRun makes ParseDependencies only list dependencies from go.mod, without any network calls.
+// Licenses aren't fetched and repositories aren't starred, so License of dependencies is empty and
+// table format shows it as UNKNOWN. Import paths not hosted on known hosts link to pkg.go.dev.
+func WithDryRun() Option {
+	return func(c *Client) {
+		c.dryRun = true
+	}
+}
+
+// WithInteractiveAuth obtains GitHub token with OAuth2 device flow when GitHub API key isn't set,
+// instead of using unauthenticated API limited to 60 requests per hour. Verification URL and code
+// are printed to stderr, and the token is cached in ~/.glice/token.json for later runs. Device flow
+// requires client ID of GitHub OAuth app with device flow enabled, set in GLICE_GITHUB_CLIENT_ID env variable.
+func WithInteractiveAuth() Option {
+	return func(c *Client) {
+		c.interactiveAuth = true
+	}
+}
+
+// WithDependencies sets dependencies the client prints and checks without parsing go.mod, e.g. in
+// tests or when dependencies come from another source. ParseDependencies replaces them.
+func WithDependencies(repos ...*Repository) Option {
+	return func(c *Client) {
+		c.dependencies = repos
+		c.presetDependencies = true
+	}
+}
+
+// WithExcludeStdlib skips standard library pseudo-modules and golang.org/x/ modules, for teams that
+// consider modules of Go project pre-approved. It takes precedence over WithIncludeToolchain.
+func WithExcludeStdlib() Option {
+	return func(c *Client) {
+		c.excludeStdlib = true
+	}
+}
+
+// WithGiteaHosts sets hostnames of Gitea or Forgejo instances, whose dependencies are resolved using
+// Gitea API. GITEA_API_KEY is used as API token, unless host has its own set with WithAPIKey.
+func WithGiteaHosts(hosts ...string) Option {
+	return func(c *Client) {
+		c.giteaHosts = hosts
+	}
+}
+
+// WithGitLabHosts sets hostnames of self-hosted GitLab instances, whose dependencies are resolved
+// using GitLab API. API token of each host can be set with WithAPIKey.
+func WithGitLabHosts(hosts ...string) Option {
+	return func(c *Client) {
+		c.gitlabHosts = hosts
+	}
+}
+
+// WithCSVDelimiter sets field delimiter of csv format, e.g. '\t' for TSV. Defaults to ','.
+func WithCSVDelimiter(r rune) Option {
+	return func(c *Client) {
+		c.csvDelimiter = r
+	}
+}
+
+// WithCSVBOM prepends UTF-8 byte order mark to csv format, needed by Excel on Windows to detect encoding
+func WithCSVBOM(bom bool) Option {
+	return func(c *Client) {
+		c.csvBOM = bom
+	}
+}
+
+// WithJSONIndent pretty-prints json format, each line starts with prefix followed by indent
+// repeated according to nesting. JSON is compact by default.
+func WithJSONIndent(prefix, indent string) Option {
+	return func(c *Client) {
+		c.jsonPrefix = prefix
+		c.jsonIndent = indent
+	}
+}
+
+// WithSummary appends number of dependencies per license to table format as a second table. For json
+// format, dependencies and summary are printed as "dependencies" and "summary" keys of an object.
+func WithSummary() Option {
+	return func(c *Client) {
+		c.summary = true
+	}
+}
+
+// WithLicenseOverrides sets licenses used instead of the fetched ones, keyed by import path
+func WithLicenseOverrides(overrides map[string]string) Option {
+	return func(c *Client) {
+		c.LicenseOverrides = overrides
+	}
+}
+
+// WithVendor reads licenses of vendored modules from the vendor directory instead of fetching them.
+// Modules without a license file in vendor are still fetched.
+func WithVendor() Option {
+	return func(c *Client) {
+		c.vendor = true
+	}
+}
+
+// WithVendorDir reads licenses of vendored modules from dir, defaults to vendor directory next to go.mod
+func WithVendorDir(dir string) Option {
+	return func(c *Client) {
+		c.vendor = true
+		c.vendorDir = dir
+	}
+}
+
+// WithAbandonedThreshold fetches activity of GitHub repositories along with their licenses, so that
+// CheckAbandoned reports archived dependencies and dependencies not pushed to for more than years.
+// With years of 0 only archived dependencies are reported. It costs an extra API request per repository.
+func WithAbandonedThreshold(years int) Option {
+	return func(c *Client) {
+		c.abandoned, c.abandonedYears = true, years
+	}
+}
+
+// WithFetchAllLicenseFiles fetches all license and notice files (LICENSE*, NOTICE*, COPYING*) in root
+// of GitHub repositories into Repository.LicenseFiles. It costs at least two extra API requests per repository.
+func WithFetchAllLicenseFiles() Option {
+	return func(c *Client) {
+		c.allLicenseFiles = true
+	}
+}
+
+// WithTaggedLicenses reads license files of GitHub repositories at the tag of dependency version, as
+// GitHub License API only returns license of the default branch. It costs an extra API request per repository.
+func WithTaggedLicenses() Option {
+	return func(c *Client) {
+		c.taggedLicenses = true
+	}
+}
+
+// WithGraphQL fetches licenses of GitHub repositories with GraphQL API, batching up to 20 repositories
+// in a single request instead of making one REST API request per repository. GraphQL API requires
+// GitHub API key, REST API is used without it and for repositories GraphQL query failed for.
+func WithGraphQL() Option {
+	return func(c *Client) {
+		c.graphQL = true
+	}
+}
+
+// WithSort orders printed dependencies by field (name, license, host or version) in direction
+// (asc or desc). Dependencies are printed in go.mod order by default.
+func WithSort(field, direction string) Option {
+	return func(c *Client) {
+		c.sortField, c.sortDirection = field, direction
+	}
+}
+
+// WithColor enables or disables ANSI colors of licenses and URLs. By default colors are used
+// unless NO_COLOR environment variable is set or output isn't a terminal.
+func WithColor(enabled bool) Option {
+	return func(c *Client) {
+		c.color = &enabled
+	}
+}
+
+// WithProgress shows progress bar on stderr while licenses are fetched, enabled by default when stderr is a terminal
+func WithProgress(enabled bool) Option {
+	return func(c *Client) {
+		c.progress = enabled
+	}
+}
@@ -0,0 +1,231 @@
+package glice
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClient_Options(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	hc := &http.Client{}
+	c, err := NewClient(wd(),
+		WithFormat("json"),
+		WithOutput("file"),
+		WithConcurrency(2),
+		WithTimeout(time.Second),
+		WithLogger(logger),
+		WithHTTPClient(hc),
+		WithAPIKey("github.com", "key"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.format != "json" || c.output != "file" || c.concurrency != 2 || c.timeout != time.Second ||
+		c.logger != logger || c.httpClient != hc {
+		t.Errorf("options were not applied: %+v", c)
+	}
+	if c.gitKeys()["github.com"] != "key" {
+		t.Error("expected API key from options to override environment")
+	}
+}
+
+func TestNewClient_LoggerParsing(t *testing.T) {
+	dir := t.TempDir()
+	gomod := "module example.com/a\n\ngo 1.21\n\nrequire example.com/local v1.0.0\n\nreplace example.com/local => ./local\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	logs := &bytes.Buffer{}
+	c, err := NewClient(dir, WithLogger(slog.New(slog.NewTextHandler(logs, nil))), WithDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseDependencies(false, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := logs.String(); !strings.Contains(got, "dependency=example.com/local directory=./local") {
+		t.Errorf("expected go.mod parsing to log with client logger, got %q", got)
+	}
+}
+
+func TestNewClient_Concurrency(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		if _, err := NewClient(wd(), WithConcurrency(n)); err == nil {
+			t.Errorf("expected error for concurrency %d", n)
+		}
+	}
+	if _, err := NewClient(wd(), WithConcurrency(1)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNewClient_Columns(t *testing.T) {
+	if _, err := NewClient(wd(), WithColumns("dependency", "category", "stars")); err != nil {
+		t.Error(err)
+	}
+	if _, err := NewClient(wd(), WithColumns("dependency", "downloads")); err == nil {
+		t.Error("expected error for unknown column")
+	}
+	c, err := NewClient(wd(), WithColumns(" Dependency", "LICENSE "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"dependency", "license"}; !reflect.DeepEqual(c.columns, want) {
+		t.Errorf("columns = %v, want %v", c.columns, want)
+	}
+}
+
+func TestClient_GitHubURL(t *testing.T) {
+	tests := map[string]struct {
+		option string
+		env    string
+		want   string
+	}{
+		"public github":        {},
+		"github actions":       {env: "https://api.github.com", want: ""},
+		"env":                  {env: "https://github.example.com/api/v3", want: "https://github.example.com/api/v3"},
+		"option overrides env": {option: "https://ghe.example.com/", env: "https://github.example.com/api/v3", want: "https://ghe.example.com/"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("GITHUB_API_URL", tt.env)
+			c, err := NewClient(wd(), WithGitHubBaseURL(tt.option))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.gitHubURL(); got != tt.want {
+				t.Errorf("gitHubURL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClient_Outputs(t *testing.T) {
+	c, err := NewClient(wd(), WithOutputs("stdout", "file"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c.outputs, []string{"stdout", "file"}) || c.output != "stdout" {
+		t.Errorf("unexpected outputs %v", c.outputs)
+	}
+	if _, err := NewClient(wd(), WithOutput("both")); err != nil {
+		t.Errorf("expected both output to be valid, got %v", err)
+	}
+	if _, err := NewClient(wd(), WithOutputs("stdout", "printer")); err == nil {
+		t.Error("expected error for invalid output")
+	}
+	if c, _ := NewClient(wd(), WithOutputs("stdout", "file"), WithOutput("file")); !reflect.DeepEqual(c.outputs, []string{"file"}) {
+		t.Errorf("expected WithOutput to override outputs, got %v", c.outputs)
+	}
+}
+
+func TestNewClient_Progress(t *testing.T) {
+	c, err := NewClient(wd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.progress {
+		t.Error("expected progress to be disabled when stderr is not a terminal")
+	}
+
+	c, err = NewClient(wd(), WithProgress(true), WithVendorDir(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.progress {
+		t.Error("expected progress to be enabled")
+	}
+	repos := c.resolveLicenses(context.Background(), nil, map[string]string{}, false)
+	if len(repos) != 0 {
+		t.Errorf("expected no repositories, got %v", repos)
+	}
+}
+
+func TestNewClient_Defaults(t *testing.T) {
+	c, err := NewClient(wd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.format != "table" || c.output != "stdout" || c.concurrency != defaultConcurrency || c.timeout != defaultTimeout {
+		t.Errorf("unexpected defaults: %+v", c)
+	}
+}
+
+func TestNewClientLegacy(t *testing.T) {
+	c, err := NewClientLegacy(wd(), "csv", "file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.format != "csv" || c.output != "file" {
+		t.Errorf("unexpected format and output: %s, %s", c.format, c.output)
+	}
+
+	if _, err := NewClientLegacy(wd(), "invalid", "stdout"); err == nil {
+		t.Error("expected error for invalid format")
+	}
+}
+
+func TestNewClient_Workspace(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.work"), []byte("go 1.18\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, opts := range map[string][]Option{"workspace option": {WithWorkspace()}, "go.work only": nil} {
+		c, err := NewClient(dir, opts...)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := c.ParseDependencies(false, false); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(c.dependencies) != 0 {
+			t.Errorf("%s: expected no dependencies for empty workspace, got %d", name, len(c.dependencies))
+		}
+	}
+
+	if _, err := NewClient(t.TempDir()); err != ErrNoGoMod {
+		t.Errorf("expected ErrNoGoMod without go.mod and go.work, got %v", err)
+	}
+}
+
+func TestNewClient_Color(t *testing.T) {
+	enabled, disabled := true, false
+	tests := map[string]struct {
+		noColor string
+		color   *bool
+		want    bool
+	}{
+		"not a terminal":         {want: false},
+		"NO_COLOR set":           {noColor: "1", want: false},
+		"enabled":                {color: &enabled, want: true},
+		"enabled with NO_COLOR":  {noColor: "1", color: &enabled, want: true},
+		"disabled":               {color: &disabled, want: false},
+		"empty NO_COLOR ignored": {noColor: "", color: &enabled, want: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			var opts []Option
+			if tt.color != nil {
+				opts = append(opts, WithColor(*tt.color))
+			}
+			c, err := NewClient(wd(), opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.useColor(&bytes.Buffer{}); got != tt.want {
+				t.Errorf("useColor() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package glice
+
+import "strings"
+
+// buildPURL returns package URL (https://github.com/package-url/purl-spec) of dependency:
+// pkg:golang/<module path>@<version>, as defined by golang type of the spec. Module path is used
+// even for GitHub, GitLab and Bitbucket repositories, as several modules can share a repository.
+// Version is omitted when unknown.
+func buildPURL(r *Repository) string {
+	if r.Version == "" {
+		return "pkg:golang/" + r.Name
+	}
+	// + of +incompatible versions is reserved in package URLs
+	return "pkg:golang/" + r.Name + "@" + strings.ReplaceAll(r.Version, "+", "%2B")
+}
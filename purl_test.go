@@ -0,0 +1,58 @@
+package glice
+
+import "testing"
+
+func TestBuildPURL(t *testing.T) {
+	tests := map[string]struct {
+		repo *Repository
+		want string
+	}{
+		"github": {
+			repo: &Repository{Name: "github.com/ribice/glice/v2", Host: "github.com", Author: "ribice", Project: "glice", Version: "v2.0.0"},
+			want: "pkg:golang/github.com/ribice/glice/v2@v2.0.0",
+		},
+		"gitlab": {
+			repo: &Repository{Name: "gitlab.com/xanzy/go-gitlab", Host: "gitlab.com", Author: "xanzy", Project: "go-gitlab", Version: "v0.20.1"},
+			want: "pkg:golang/gitlab.com/xanzy/go-gitlab@v0.20.1",
+		},
+		"bitbucket": {
+			repo: &Repository{Name: "bitbucket.org/ribice/kiss", Host: "bitbucket.org", Author: "ribice", Project: "kiss", Version: "v1.0.0"},
+			want: "pkg:golang/bitbucket.org/ribice/kiss@v1.0.0",
+		},
+		"gopkg.in resolved to github": {
+			repo: &Repository{Name: "gopkg.in/yaml.v3", Host: "github.com", Author: "go-yaml", Project: "yaml", Version: "v3.0.1"},
+			want: "pkg:golang/gopkg.in/yaml.v3@v3.0.1",
+		},
+		"vanity import path": {
+			repo: &Repository{Name: "golang.org/x/mod", Host: "go.googlesource.com", Project: "mod", Version: "v0.8.0"},
+			want: "pkg:golang/golang.org/x/mod@v0.8.0",
+		},
+		"self-hosted": {
+			repo: &Repository{Name: "git.company.com/team/lib", Host: "git.company.com", Author: "team", Project: "lib", Version: "v1.0.0"},
+			want: "pkg:golang/git.company.com/team/lib@v1.0.0",
+		},
+		"incompatible version": {
+			repo: &Repository{Name: "github.com/some/lib", Host: "github.com", Author: "some", Project: "lib", Version: "v2.1.0+incompatible"},
+			want: "pkg:golang/github.com/some/lib@v2.1.0%2Bincompatible",
+		},
+		"no version": {
+			repo: &Repository{Name: "github.com/ribice", Host: "github.com"},
+			want: "pkg:golang/github.com/ribice",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := buildPURL(tt.repo); got != tt.want {
+				t.Errorf("buildPURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildPURLSameRepository(t *testing.T) {
+	root := &Repository{Name: "github.com/x/y", Host: "github.com", Author: "x", Project: "y", Version: "v1.2.0"}
+	sub := &Repository{Name: "github.com/x/y/sub", Host: "github.com", Author: "x", Project: "y", Version: "v1.2.0"}
+	if a, b := buildPURL(root), buildPURL(sub); a == b {
+		t.Errorf("expected different package URLs of modules in the same repository, got %q for both", a)
+	}
+}
@@ -0,0 +1,110 @@
+package glice
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxBackoff caps exponential backoff between retries
+const maxBackoff = 30 * time.Second
+
+type retryPolicy struct {
+	maxAttempts int
+	base        time.Duration
+}
+
+// do runs API request fn, retrying transient failures with exponential backoff.
+// Each attempt is limited by request timeout and retrying stops once ctx is done.
+// Transient failure that isn't retried anymore is returned as transientError.
+func (gc *gitClient) do(ctx context.Context, fn func(context.Context) (*http.Response, error)) error {
+	attempts := gc.retry.maxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		rctx, cancel := gc.requestContext(ctx)
+		resp, err := fn(rctx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		if !isTransient(ctx, resp) {
+			return err
+		}
+		if attempt >= attempts {
+			return transientError{err}
+		}
+
+		wait := backoff(gc.retry.base, attempt)
+		if d, ok := retryAfter(resp); ok {
+			wait = d
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// isTransient reports whether failed request is worth retrying. Requests that failed
+// without response (network errors, timeouts) are retried unless ctx is done.
+func isTransient(ctx context.Context, resp *http.Response) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if resp == nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// transientError wraps error of request that failed transiently, see isTransient
+type transientError struct {
+	err error
+}
+
+func (e transientError) Error() string { return e.err.Error() }
+
+func (e transientError) Unwrap() error { return e.err }
+
+// isTransientError reports whether err is caused by network error, 429 or 5xx response
+func isTransientError(err error) bool {
+	var te transientError
+	return errors.As(err, &te)
+}
+
+func backoff(base time.Duration, attempt int) time.Duration {
+	d := base
+	for i := 1; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff {
+		return maxBackoff
+	}
+	return d
+}
+
+// retryAfter parses Retry-After header of 429 Too Many Requests response
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	v := resp.Header.Get("Retry-After")
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,112 @@
+package glice
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := map[int]time.Duration{
+		1:  time.Second,
+		2:  2 * time.Second,
+		3:  4 * time.Second,
+		6:  maxBackoff,
+		50: maxBackoff,
+	}
+	for attempt, want := range tests {
+		if got := backoff(time.Second, attempt); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"3"}}}
+	if d, ok := retryAfter(resp); !ok || d != 3*time.Second {
+		t.Errorf("retryAfter() = %v, %v, want 3s", d, ok)
+	}
+
+	resp.StatusCode = http.StatusBadGateway
+	if _, ok := retryAfter(resp); ok {
+		t.Error("expected Retry-After to be used only for 429 responses")
+	}
+}
+
+func TestGitClient_Retry(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			fmt.Fprint(w, "Permission is hereby granted, free of charge, to any person obtaining a copy")
+		}
+	}))
+	defer srv.Close()
+
+	c := context.Background()
+	gc := newGitClient(c, map[string]string{}, false, withRetry(retryPolicy{maxAttempts: 3, base: time.Millisecond}))
+	gc.bb.baseURL = srv.URL
+
+	l := &Repository{Host: "bitbucket.org", Author: "ribice", Project: "kiss"}
+	if err := gc.GetLicense(c, l); err != nil {
+		t.Fatal(err)
+	}
+	if l.License != "MIT" || atomic.LoadInt32(&calls) != 3 {
+		t.Errorf("expected license after 3 attempts, got %q after %d", l.License, calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	gc.retry.maxAttempts = 2
+	if err := gc.GetLicense(c, l); err == nil {
+		t.Error("expected error once attempts are exhausted")
+	}
+}
+
+func TestGitClient_RetryNotFound(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	c := context.Background()
+	gc := newGitClient(c, map[string]string{}, false, withRetry(retryPolicy{maxAttempts: 3, base: time.Millisecond}))
+	gc.bb.baseURL = srv.URL
+
+	if err := gc.GetLicense(c, &Repository{Host: "bitbucket.org", Author: "ribice", Project: "kiss"}); err == nil {
+		t.Error("expected not found error")
+	}
+	if calls != 1 {
+		t.Errorf("expected not found response not to be retried, got %d calls", calls)
+	}
+}
+
+func TestGitClient_RetryCancelled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	gc := newGitClient(ctx, map[string]string{}, false, withRetry(retryPolicy{maxAttempts: 10, base: time.Minute}))
+	gc.bb.baseURL = srv.URL
+
+	start := time.Now()
+	if err := gc.GetLicense(ctx, &Repository{Host: "bitbucket.org", Author: "ribice", Project: "kiss"}); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("retrying didn't stop after context was done")
+	}
+}
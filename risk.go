@@ -0,0 +1,70 @@
+package glice
+
+// unknownRiskScore is risk score of licenses that aren't recognized. Code without known license
+// can't be safely redistributed, so it's treated almost as restrictive as strong copyleft.
+const unknownRiskScore = 8
+
+var licenseRiskScores = map[string]int{
+	"unlicense":    0,
+	"cc0-1.0":      0,
+	"0bsd":         0,
+	"mit":          1,
+	"isc":          1,
+	"zlib":         1,
+	"wtfpl":        1,
+	"ms-pl":        2,
+	"bsd-2-clause": 1,
+	"bsl-1.0":      1,
+	"bsd-3-clause": 2,
+	"apache-2.0":   2,
+	"artistic-1.0": 3,
+	"artistic-2.0": 3,
+	"mpl-2.0":      4,
+	"epl-2.0":      5,
+	"lgpl-2.1":     5,
+	"cddl-1.0":     5,
+	"ms-rl":        5,
+	"lgpl-3.0":     6,
+	// classpath exception allows linking without the rest of the program becoming GPL
+	"gpl-2.0-with-classpath-exception": 6,
+	"gpl-3.0":                          9,
+	"eupl-1.2":                         9,
+	"osl-3.0":                          10,
+	"gpl-2.0":                          10,
+	"agpl-3.0":                         10,
+}
+
+// LicenseRiskScore returns how restrictive license is by its SPDX identifier (case-insensitive), from 0
+// for public domain licenses to 10 for AGPL-3.0 and GPL-2.0. Unrecognized licenses score 8.
+func LicenseRiskScore(spdxID string) int {
+	if score, ok := licenseRiskScores[licenseFamily(spdxID)]; ok {
+		return score
+	}
+	return unknownRiskScore
+}
+
+// classifyLicense sets category and risk score of r from its license
+func classifyLicense(r *Repository) {
+	r.Category = LicenseCategory(r.License)
+	r.RiskScore = LicenseRiskScore(r.License)
+}
+
+// TotalRiskScore returns sum of risk scores of all dependencies
+func (c *Client) TotalRiskScore() int {
+	var total int
+	for _, d := range c.dependencies {
+		total += d.RiskScore
+	}
+	return total
+}
+
+// HighRiskDependencies returns dependencies whose risk score is at least threshold
+func (c *Client) HighRiskDependencies(threshold int) []*Repository {
+	var risky []*Repository
+	for _, d := range c.dependencies {
+		if d.RiskScore >= threshold {
+			risky = append(risky, d)
+		}
+	}
+	return risky
+}
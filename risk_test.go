@@ -0,0 +1,53 @@
+package glice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLicenseRiskScore(t *testing.T) {
+	tests := map[string]struct {
+		spdxID string
+		want   int
+	}{
+		"public domain":    {spdxID: "Unlicense", want: 0},
+		"permissive":       {spdxID: "MIT", want: 1},
+		"weak copyleft":    {spdxID: "mpl-2.0", want: 4},
+		"classpath":        {spdxID: "GPL-2.0-with-classpath-exception", want: 6},
+		"strong copyleft":  {spdxID: "GPL-2.0", want: 10},
+		"network copyleft": {spdxID: "AGPL-3.0", want: 10},
+		"or later":         {spdxID: "GPL-3.0-or-later", want: 9},
+		"unknown":          {spdxID: "Other", want: unknownRiskScore},
+		"empty":            {want: unknownRiskScore},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := LicenseRiskScore(tt.spdxID); got != tt.want {
+				t.Errorf("LicenseRiskScore(%q) = %d, want %d", tt.spdxID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_RiskScore(t *testing.T) {
+	mit := &Repository{Name: "github.com/fatih/color", License: "MIT"}
+	gpl := &Repository{Name: "github.com/some/gpl", License: "GPL-3.0"}
+	none := &Repository{Name: "github.com/some/none"}
+	for _, r := range []*Repository{mit, gpl, none} {
+		classifyLicense(r)
+	}
+	if gpl.Category != CategoryStrongCopyleft || gpl.RiskScore != 9 {
+		t.Errorf("unexpected category and risk score of GPL-3.0 dependency %+v", gpl)
+	}
+
+	c := &Client{dependencies: []*Repository{mit, gpl, none}}
+	if got := c.TotalRiskScore(); got != 1+9+unknownRiskScore {
+		t.Errorf("TotalRiskScore() = %d, want %d", got, 1+9+unknownRiskScore)
+	}
+	if got := c.HighRiskDependencies(8); !reflect.DeepEqual(got, []*Repository{gpl, none}) {
+		t.Errorf("HighRiskDependencies(8) = %v, want GPL and unknown dependencies", got)
+	}
+	if got := c.HighRiskDependencies(11); got != nil {
+		t.Errorf("HighRiskDependencies(11) = %v, want none", got)
+	}
+}
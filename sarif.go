@@ -0,0 +1,126 @@
+package glice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ribice/glice/v2/mod"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// sarifLog is a subset of SARIF 2.1.0 log, see
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// printSARIF reports dependencies with blocked or unknown licenses as SARIF results located
+// at their require directive in go.mod
+func (c *Client) printSARIF(writeTo io.Writer) error {
+	lines, err := mod.RequireLines(c.path)
+	// without go.mod (e.g. workspace root or dependencies set with WithDependencies), results have no region
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "glice",
+			InformationURI: "https://github.com/ribice/glice",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	rules := map[string]bool{}
+	for _, d := range c.dependencies {
+		var level, text string
+		switch {
+		case containsFold(c.BlockedLicenses, d.License):
+			level, text = "error", fmt.Sprintf("%s uses blocked license %s", d.Name, d.License)
+		case isUnknownLicense(d):
+			level, text = "warning", fmt.Sprintf("%s uses unknown license %q", d.Name, d.License)
+		default:
+			continue
+		}
+
+		id := spdxLicense(d)
+		if !rules[id] {
+			rules[id] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:               id,
+				ShortDescription: sarifMessage{Text: "Dependency license " + id},
+			})
+		}
+
+		loc := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: "go.mod"}}
+		if line, ok := lines[d.Name]; ok {
+			loc.Region = &sarifRegion{StartLine: line}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    id,
+			Level:     level,
+			Message:   sarifMessage{Text: text},
+			Locations: []sarifLocation{{PhysicalLocation: loc}},
+		})
+	}
+
+	return json.NewEncoder(writeTo).Encode(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}})
+}
@@ -0,0 +1,56 @@
+package glice
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ribice/glice/v2/mod"
+)
+
+func TestClient_PrintSARIF(t *testing.T) {
+	c := &Client{path: wd(), format: "sarif", output: "stdout", BlockedLicenses: []string{"gpl-3.0"}, dependencies: []*Repository{
+		{Name: "github.com/fatih/color", License: "MIT", Version: "v1.17.0"},
+		{Name: "github.com/gocolly/colly", License: "GPL-3.0", Version: "v1.2.0"},
+		{Name: "github.com/some/other", License: "Other"},
+		{Name: "github.com/some/zerobsd", License: "0BSD"},
+		{Name: "github.com/some/none"},
+	}}
+
+	output := &bytes.Buffer{}
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+
+	var got sarifLog
+	if err := json.Unmarshal(output.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != "2.1.0" || len(got.Runs) != 1 {
+		t.Fatalf("unexpected SARIF log: %+v", got)
+	}
+
+	run := got.Runs[0]
+	// uncategorized SPDX license isn't reported, dependencies without license share rule of unknown ones
+	if len(run.Tool.Driver.Rules) != 2 || len(run.Results) != 3 {
+		t.Fatalf("expected 2 rules and 3 results, got %+v", run)
+	}
+
+	lines, err := mod.RequireLines(wd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocked := run.Results[0]
+	if blocked.RuleID != "GPL-3.0" || blocked.Level != "error" {
+		t.Errorf("unexpected blocked result: %+v", blocked)
+	}
+	loc := blocked.Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "go.mod" || loc.Region == nil || loc.Region.StartLine != lines["github.com/gocolly/colly"] {
+		t.Errorf("unexpected blocked result location: %+v", loc)
+	}
+
+	unknown := run.Results[1]
+	if unknown.RuleID != spdxNoAssertion || unknown.Level != "warning" || unknown.Locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("unexpected unknown result: %+v", unknown)
+	}
+}
@@ -0,0 +1,93 @@
+package glice
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultServeTTL is how long Serve reuses parsed dependencies unless set with WithServeTTL
+const defaultServeTTL = 5 * time.Minute
+
+// Serve starts HTTP server on addr (e.g. ":8080") serving dependencies of go.mod at path as JSON.
+// GET /licenses returns all dependencies and GET /licenses/{module-path} a single one.
+// Dependencies are parsed on the first request and reused for the TTL set with WithServeTTL.
+func Serve(path string, indirect bool, addr string, opts ...Option) error {
+	c, err := NewClient(path, opts...)
+	if err != nil {
+		return err
+	}
+	return http.ListenAndServe(addr, c.licenseHandler(indirect))
+}
+
+// licenseHandler returns handler serving dependencies of c, parsing them again once TTL expires
+func (c *Client) licenseHandler(indirect bool) *licenseHandler {
+	ttl := c.serveTTL
+	if ttl <= 0 {
+		ttl = defaultServeTTL
+	}
+	return &licenseHandler{c: c, indirect: indirect, ttl: ttl, now: time.Now}
+}
+
+type licenseHandler struct {
+	c        *Client
+	indirect bool
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu     sync.Mutex
+	parsed time.Time
+	deps   []*Repository
+}
+
+// dependencies returns parsed dependencies, parsing them if they weren't parsed yet or TTL expired
+func (h *licenseHandler) dependencies() ([]*Repository, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.parsed.IsZero() && h.now().Sub(h.parsed) < h.ttl {
+		return h.deps, nil
+	}
+	if err := h.c.ParseDependencies(h.indirect, false); err != nil {
+		return nil, err
+	}
+	h.deps, h.parsed = h.c.dependencies, h.now()
+	return h.deps, nil
+}
+
+func (h *licenseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name, single := strings.CutPrefix(r.URL.Path, "/licenses/")
+	if !single && r.URL.Path != "/licenses" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	deps, err := h.dependencies()
+	if err != nil {
+		h.c.log().Error("Could not parse dependencies", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !single {
+		writeJSON(w, deps)
+		return
+	}
+	for _, d := range deps {
+		if d.Name == name {
+			writeJSON(w, d)
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
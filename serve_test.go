@@ -0,0 +1,90 @@
+package glice
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLicenseHandler(t *testing.T) {
+	now := time.Now()
+	deps := []*Repository{
+		{Name: "github.com/ribice/glice", License: "MIT"},
+		{Name: "github.com/fatih/color", License: "MIT"},
+	}
+	h := (&Client{}).licenseHandler(false)
+	h.deps, h.parsed, h.now = deps, now, func() time.Time { return now }
+
+	tests := map[string]struct {
+		method   string
+		path     string
+		wantCode int
+		wantName string
+		wantLen  int
+	}{
+		"all":          {path: "/licenses", wantCode: http.StatusOK, wantLen: 2},
+		"single":       {path: "/licenses/github.com/fatih/color", wantCode: http.StatusOK, wantName: "github.com/fatih/color"},
+		"missing":      {path: "/licenses/github.com/ribice/kiss", wantCode: http.StatusNotFound},
+		"unknown":      {path: "/dependencies", wantCode: http.StatusNotFound},
+		"wrong method": {method: http.MethodPost, path: "/licenses", wantCode: http.StatusMethodNotAllowed},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(method, tt.path, nil))
+			if rec.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantCode, rec.Code, rec.Body.String())
+			}
+			switch {
+			case tt.wantLen > 0:
+				var got []*Repository
+				if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || len(got) != tt.wantLen {
+					t.Errorf("expected %d dependencies, got %s (%v)", tt.wantLen, rec.Body.String(), err)
+				}
+			case tt.wantName != "":
+				var got Repository
+				if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || got.Name != tt.wantName {
+					t.Errorf("expected %s, got %s (%v)", tt.wantName, rec.Body.String(), err)
+				}
+			}
+		})
+	}
+}
+
+func TestLicenseHandler_TTL(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/a\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewClient(dir, WithServeTTL(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := c.licenseHandler(false)
+	if h.ttl != time.Minute {
+		t.Errorf("expected TTL set with WithServeTTL, got %v", h.ttl)
+	}
+
+	cached := []*Repository{{Name: "github.com/ribice/glice"}}
+	now := time.Now()
+	h.deps, h.parsed, h.now = cached, now, func() time.Time { return now.Add(30 * time.Second) }
+	if deps, err := h.dependencies(); err != nil || len(deps) != 1 {
+		t.Errorf("expected cached dependencies within TTL, got %v, %v", deps, err)
+	}
+
+	h.now = func() time.Time { return now.Add(2 * time.Minute) }
+	if deps, err := h.dependencies(); err != nil || len(deps) != 0 {
+		t.Errorf("expected dependencies to be parsed again after TTL, got %v, %v", deps, err)
+	}
+	if (&Client{}).licenseHandler(false).ttl != defaultServeTTL {
+		t.Error("expected default TTL")
+	}
+}
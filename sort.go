@@ -0,0 +1,38 @@
+package glice
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// sortFields compare dependencies by field passed to WithSort, returning negative number when a is before b
+var sortFields = map[string]func(a, b *Repository) int{
+	"name":    func(a, b *Repository) int { return compareFold(a.Name, b.Name) },
+	"license": func(a, b *Repository) int { return compareFold(a.License, b.License) },
+	"host":    func(a, b *Repository) int { return compareFold(a.Host, b.Host) },
+	"version": func(a, b *Repository) int { return semver.Compare(a.Version, b.Version) },
+}
+
+var validSortDirections = map[string]bool{"asc": true, "desc": true}
+
+func compareFold(a, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// sortDependencies orders dependencies by field set with WithSort, keeping go.mod order of equal ones.
+// Dependencies are kept in go.mod order when sorting isn't configured.
+func (c *Client) sortDependencies() {
+	cmp, ok := sortFields[c.sortField]
+	if !ok {
+		return
+	}
+	desc := c.sortDirection == "desc"
+	sort.SliceStable(c.dependencies, func(i, j int) bool {
+		if desc {
+			return cmp(c.dependencies[j], c.dependencies[i]) < 0
+		}
+		return cmp(c.dependencies[i], c.dependencies[j]) < 0
+	})
+}
@@ -0,0 +1,78 @@
+package glice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClient_SortDependencies(t *testing.T) {
+	deps := func() []*Repository {
+		return []*Repository{
+			{Name: "gopkg.in/yaml.v3", License: "MIT", Host: "github.com", Version: "v3.0.1"},
+			{Name: "github.com/fatih/color", License: "MIT", Host: "github.com", Version: "v1.17.0"},
+			{Name: "gitlab.com/xanzy/go-gitlab", License: "Apache-2.0", Host: "gitlab.com", Version: "v0.20.1"},
+			{Name: "bitbucket.org/some/lib", License: "bsd-3-clause", Host: "bitbucket.org", Version: "v1.2.0"},
+		}
+	}
+	tests := map[string]struct {
+		field, direction string
+		want             []string
+	}{
+		"unsorted": {
+			want: []string{"gopkg.in/yaml.v3", "github.com/fatih/color", "gitlab.com/xanzy/go-gitlab", "bitbucket.org/some/lib"},
+		},
+		"name asc": {
+			field: "name", direction: "asc",
+			want: []string{"bitbucket.org/some/lib", "github.com/fatih/color", "gitlab.com/xanzy/go-gitlab", "gopkg.in/yaml.v3"},
+		},
+		"name desc": {
+			field: "name", direction: "desc",
+			want: []string{"gopkg.in/yaml.v3", "gitlab.com/xanzy/go-gitlab", "github.com/fatih/color", "bitbucket.org/some/lib"},
+		},
+		"license is case insensitive and stable": {
+			field: "license", direction: "asc",
+			want: []string{"gitlab.com/xanzy/go-gitlab", "bitbucket.org/some/lib", "gopkg.in/yaml.v3", "github.com/fatih/color"},
+		},
+		"host desc": {
+			field: "host", direction: "desc",
+			want: []string{"gitlab.com/xanzy/go-gitlab", "gopkg.in/yaml.v3", "github.com/fatih/color", "bitbucket.org/some/lib"},
+		},
+		"version uses semver": {
+			field: "version", direction: "asc",
+			want: []string{"gitlab.com/xanzy/go-gitlab", "bitbucket.org/some/lib", "github.com/fatih/color", "gopkg.in/yaml.v3"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Client{dependencies: deps()}
+			WithSort(tt.field, tt.direction)(c)
+			c.sortDependencies()
+			var got []string
+			for _, d := range c.dependencies {
+				got = append(got, d.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortDependencies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClient_Sort(t *testing.T) {
+	tests := map[string]struct {
+		field, direction string
+		wantErr          bool
+	}{
+		"valid":             {field: "license", direction: "desc"},
+		"invalid field":     {field: "stars", direction: "asc", wantErr: true},
+		"invalid direction": {field: "name", direction: "up", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewClient(wd(), WithSort(tt.field, tt.direction))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewClient() error = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+}
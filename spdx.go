@@ -0,0 +1,267 @@
+package glice
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ribice/glice/v2/mod"
+)
+
+const (
+	spdxVersion     = "SPDX-2.3"
+	spdxDataLicense = "CC0-1.0"
+	spdxDocumentID  = "SPDXRef-DOCUMENT"
+	spdxNoAssertion = "NOASSERTION"
+	spdxCreator     = "Tool: glice"
+)
+
+type spdxDocument struct {
+	SPDXID            string             `json:"SPDXID"`
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string `json:"SPDXID"`
+	Name             string `json:"name"`
+	VersionInfo      string `json:"versionInfo,omitempty"`
+	DownloadLocation string `json:"downloadLocation"`
+	FilesAnalyzed    bool   `json:"filesAnalyzed"`
+	LicenseConcluded string `json:"licenseConcluded"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+	RelationshipType   string `json:"relationshipType"`
+}
+
+func (c *Client) printSPDXJSON(writeTo io.Writer) error {
+	return json.NewEncoder(writeTo).Encode(c.spdxDocument())
+}
+
+// printSPDXTagValue writes SPDX document in tag-value format, see
+// https://spdx.github.io/spdx-spec/v2.3/document-creation-information/
+func (c *Client) printSPDXTagValue(writeTo io.Writer) error {
+	doc := c.spdxDocument()
+	w := &errWriter{w: writeTo}
+	w.printf("SPDXVersion: %s\n", doc.SPDXVersion)
+	w.printf("DataLicense: %s\n", doc.DataLicense)
+	w.printf("SPDXID: %s\n", doc.SPDXID)
+	w.printf("DocumentName: %s\n", doc.Name)
+	w.printf("DocumentNamespace: %s\n", doc.DocumentNamespace)
+	for _, cr := range doc.CreationInfo.Creators {
+		w.printf("Creator: %s\n", cr)
+	}
+	w.printf("Created: %s\n", doc.CreationInfo.Created)
+
+	for _, p := range doc.Packages {
+		w.printf("\n##### Package: %s\n\n", p.Name)
+		w.printf("PackageName: %s\n", p.Name)
+		w.printf("SPDXID: %s\n", p.SPDXID)
+		if p.VersionInfo != "" {
+			w.printf("PackageVersion: %s\n", p.VersionInfo)
+		}
+		w.printf("PackageDownloadLocation: %s\n", p.DownloadLocation)
+		w.printf("FilesAnalyzed: %t\n", p.FilesAnalyzed)
+		w.printf("PackageLicenseConcluded: %s\n", p.LicenseConcluded)
+		w.printf("PackageLicenseDeclared: %s\n", p.LicenseConcluded)
+	}
+
+	if len(doc.Relationships) > 0 {
+		w.printf("\n")
+	}
+	for _, r := range doc.Relationships {
+		w.printf("Relationship: %s %s %s\n", r.SPDXElementID, r.RelationshipType, r.RelatedSPDXElement)
+	}
+	return w.err
+}
+
+func (c *Client) spdxDocument() spdxDocument {
+	name := c.moduleName()
+	doc := spdxDocument{
+		SPDXID:            spdxDocumentID,
+		SPDXVersion:       spdxVersion,
+		DataLicense:       spdxDataLicense,
+		Name:              name,
+		DocumentNamespace: spdxNamespace(name),
+		CreationInfo: spdxCreationInfo{
+			Created:  time.Now().UTC().Format(time.RFC3339),
+			Creators: []string{spdxCreator},
+		},
+	}
+
+	for _, d := range c.dependencies {
+		id := spdxPackageID(d)
+		doc.Packages = append(doc.Packages, spdxPackage{
+			SPDXID:           id,
+			Name:             d.Name,
+			VersionInfo:      d.Version,
+			DownloadLocation: spdxOrNoAssertion(d.URL),
+			LicenseConcluded: spdxLicense(d),
+		})
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      spdxDocumentID,
+			RelatedSPDXElement: id,
+			RelationshipType:   "DESCRIBES",
+		})
+	}
+
+	return doc
+}
+
+// errWriter remembers first write error so formatted output doesn't need to check each write
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *errWriter) printf(format string, a ...interface{}) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = fmt.Fprintf(e.w, format, a...)
+}
+
+// moduleName returns module path of the scanned go.mod, falling back to directory name
+func (c *Client) moduleName() string {
+	if name, err := mod.ModulePath(c.path); err == nil && name != "" {
+		return name
+	}
+	return filepath.Base(c.path)
+}
+
+func spdxNamespace(name string) string {
+	return fmt.Sprintf("https://spdx.org/spdxdocs/%s-%d", name, time.Now().UnixNano())
+}
+
+var spdxInvalidIDChars = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+
+func spdxPackageID(r *Repository) string {
+	return "SPDXRef-Package-" + spdxInvalidIDChars.ReplaceAllString(r.Name+"-"+r.Version, "-")
+}
+
+var spdxLicenseID = regexp.MustCompile(`^[a-zA-Z0-9.+-]+$`)
+
+// spdxIDs maps lowercase license keys, as reported by GitHub, GitLab or detected from license text,
+// to canonical SPDX identifiers, along with common variants of them. GPL family keys without suffix
+// map to -only identifiers, as their plain identifiers are deprecated.
+var spdxIDs = map[string]string{
+	"0bsd":                             "0BSD",
+	"afl-3.0":                          "AFL-3.0",
+	"agpl-3.0":                         "AGPL-3.0-only",
+	"agpl-3.0-only":                    "AGPL-3.0-only",
+	"agpl-3.0-or-later":                "AGPL-3.0-or-later",
+	"apache-1.1":                       "Apache-1.1",
+	"apache-2.0":                       "Apache-2.0",
+	"apache-2":                         "Apache-2.0",
+	"apache2":                          "Apache-2.0",
+	"artistic-1.0":                     "Artistic-1.0",
+	"artistic-2.0":                     "Artistic-2.0",
+	"bsd-2-clause":                     "BSD-2-Clause",
+	"bsd-3-clause":                     "BSD-3-Clause",
+	"bsd-3-clause-clear":               "BSD-3-Clause-Clear",
+	"bsd-4-clause":                     "BSD-4-Clause",
+	"bsl-1.0":                          "BSL-1.0",
+	"cc-by-4.0":                        "CC-BY-4.0",
+	"cc-by-sa-4.0":                     "CC-BY-SA-4.0",
+	"cc0-1.0":                          "CC0-1.0",
+	"cddl-1.0":                         "CDDL-1.0",
+	"ecl-2.0":                          "ECL-2.0",
+	"epl-1.0":                          "EPL-1.0",
+	"epl-2.0":                          "EPL-2.0",
+	"eupl-1.1":                         "EUPL-1.1",
+	"eupl-1.2":                         "EUPL-1.2",
+	"gpl-2.0":                          "GPL-2.0-only",
+	"gpl-2.0+":                         "GPL-2.0-or-later",
+	"gpl-2.0-only":                     "GPL-2.0-only",
+	"gpl-2.0-or-later":                 "GPL-2.0-or-later",
+	"gpl-2.0-with-classpath-exception": "GPL-2.0-with-classpath-exception",
+	"gpl-3.0":                          "GPL-3.0-only",
+	"gpl-3.0+":                         "GPL-3.0-or-later",
+	"gpl-3.0-only":                     "GPL-3.0-only",
+	"gpl-3.0-or-later":                 "GPL-3.0-or-later",
+	"gplv2":                            "GPL-2.0-only",
+	"gplv3":                            "GPL-3.0-only",
+	"isc":                              "ISC",
+	"lgpl-2.1":                         "LGPL-2.1-only",
+	"lgpl-2.1+":                        "LGPL-2.1-or-later",
+	"lgpl-2.1-only":                    "LGPL-2.1-only",
+	"lgpl-2.1-or-later":                "LGPL-2.1-or-later",
+	"lgpl-3.0":                         "LGPL-3.0-only",
+	"lgpl-3.0+":                        "LGPL-3.0-or-later",
+	"lgpl-3.0-only":                    "LGPL-3.0-only",
+	"lgpl-3.0-or-later":                "LGPL-3.0-or-later",
+	"lppl-1.3c":                        "LPPL-1.3c",
+	"mit":                              "MIT",
+	"mit-0":                            "MIT-0",
+	"mpl-1.1":                          "MPL-1.1",
+	"mpl-2.0":                          "MPL-2.0",
+	"ms-pl":                            "MS-PL",
+	"ms-rl":                            "MS-RL",
+	"ncsa":                             "NCSA",
+	"ofl-1.1":                          "OFL-1.1",
+	"osl-3.0":                          "OSL-3.0",
+	"postgresql":                       "PostgreSQL",
+	"unicode-dfs-2016":                 "Unicode-DFS-2016",
+	"unlicense":                        "Unlicense",
+	"upl-1.0":                          "UPL-1.0",
+	"vim":                              "Vim",
+	"wtfpl":                            "WTFPL",
+	"zlib":                             "Zlib",
+}
+
+// NormalizeSPDX returns canonical SPDX identifier of license key, e.g. Apache-2.0 for apache-2.0.
+// Keys are matched case-insensitively, with spaces treated as dashes (e.g. "Apache 2.0"), and
+// unknown keys are returned as they are.
+func NormalizeSPDX(key string) string {
+	key = strings.TrimSpace(key)
+	if id, ok := spdxIDs[strings.ReplaceAll(strings.ToLower(key), " ", "-")]; ok {
+		return id
+	}
+	return key
+}
+
+// licenseFamily returns lowercase key of license without -only, -or-later or + suffix, under which
+// its category and risk score are looked up
+func licenseFamily(spdxID string) string {
+	id := strings.ToLower(spdxID)
+	for _, suffix := range []string{"-only", "-or-later", "+"} {
+		id = strings.TrimSuffix(id, suffix)
+	}
+	return id
+}
+
+// spdxLicense returns SPDX identifier of dependency license, falling back to license name if it
+// is usable as SPDX identifier, NOASSERTION otherwise
+func spdxLicense(r *Repository) string {
+	if r.LicenseSPDX != "" {
+		return r.LicenseSPDX
+	}
+	if !spdxLicenseID.MatchString(r.License) || isUnknownLicense(r) {
+		return spdxNoAssertion
+	}
+	return r.License
+}
+
+func spdxOrNoAssertion(s string) string {
+	if s == "" {
+		return spdxNoAssertion
+	}
+	return s
+}
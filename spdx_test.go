@@ -0,0 +1,120 @@
+package glice
+
+import (
+	"bytes"
+	"testing"
+
+	spdxjson "github.com/spdx/tools-golang/json"
+	"github.com/spdx/tools-golang/tagvalue"
+)
+
+func TestClient_PrintSPDXJSON(t *testing.T) {
+	c := &Client{path: wd(), format: "spdx-json", output: "stdout", dependencies: []*Repository{
+		{Name: "github.com/ribice/glice", URL: "https://github.com/ribice/glice", License: "MIT", Version: "v1.0.0"},
+		{Name: "golang.org/x/mod", URL: "https://pkg.go.dev/golang.org/x/mod", License: "Other", Version: "v0.20.0"},
+	}}
+
+	output := &bytes.Buffer{}
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := spdxjson.Read(output)
+	if err != nil {
+		t.Fatalf("output is not a valid SPDX JSON document: %v", err)
+	}
+
+	if doc.SPDXVersion != spdxVersion || doc.DataLicense != spdxDataLicense || doc.DocumentName != "github.com/ribice/glice/v2" {
+		t.Errorf("unexpected document header: %s, %s, %s", doc.SPDXVersion, doc.DataLicense, doc.DocumentName)
+	}
+	if doc.DocumentNamespace == "" || doc.CreationInfo == nil || len(doc.CreationInfo.Creators) != 1 {
+		t.Error("document namespace and creation info are required")
+	}
+	if len(doc.Packages) != 2 || len(doc.Relationships) != 2 {
+		t.Fatalf("expected 2 packages and relationships, got %d and %d", len(doc.Packages), len(doc.Relationships))
+	}
+
+	pkg := doc.Packages[0]
+	if pkg.PackageName != "github.com/ribice/glice" || pkg.PackageVersion != "v1.0.0" ||
+		pkg.PackageDownloadLocation != "https://github.com/ribice/glice" || pkg.PackageLicenseConcluded != "MIT" {
+		t.Errorf("unexpected package: %+v", pkg)
+	}
+	if doc.Packages[1].PackageLicenseConcluded != spdxNoAssertion {
+		t.Errorf("expected NOASSERTION for unknown license, got %s", doc.Packages[1].PackageLicenseConcluded)
+	}
+	if rel := doc.Relationships[0]; rel.Relationship != "DESCRIBES" || string(rel.RefB.ElementRefID) != string(pkg.PackageSPDXIdentifier) {
+		t.Errorf("unexpected relationship: %+v", rel)
+	}
+}
+
+func TestClient_PrintSPDXTagValue(t *testing.T) {
+	c := &Client{path: wd(), format: "spdx-tv", output: "stdout", dependencies: []*Repository{
+		{Name: "github.com/ribice/glice", URL: "https://github.com/ribice/glice", License: "MIT", Version: "v1.0.0"},
+		{Name: "golang.org/x/mod", License: "Other"},
+	}}
+
+	output := &bytes.Buffer{}
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := tagvalue.Read(output)
+	if err != nil {
+		t.Fatalf("output is not a valid SPDX tag-value document: %v", err)
+	}
+
+	if doc.SPDXVersion != spdxVersion || doc.DataLicense != spdxDataLicense || doc.DocumentName != "github.com/ribice/glice/v2" {
+		t.Errorf("unexpected document header: %s, %s, %s", doc.SPDXVersion, doc.DataLicense, doc.DocumentName)
+	}
+	if len(doc.Packages) != 2 || len(doc.Relationships) != 2 {
+		t.Fatalf("expected 2 packages and relationships, got %d and %d", len(doc.Packages), len(doc.Relationships))
+	}
+
+	pkg := doc.Packages[0]
+	if pkg.PackageName != "github.com/ribice/glice" || pkg.PackageVersion != "v1.0.0" ||
+		pkg.PackageDownloadLocation != "https://github.com/ribice/glice" ||
+		pkg.PackageLicenseConcluded != "MIT" || pkg.PackageLicenseDeclared != "MIT" {
+		t.Errorf("unexpected package: %+v", pkg)
+	}
+	if pkg := doc.Packages[1]; pkg.PackageDownloadLocation != spdxNoAssertion || pkg.PackageLicenseDeclared != spdxNoAssertion {
+		t.Errorf("expected NOASSERTION for missing values, got %+v", pkg)
+	}
+}
+
+func TestNormalizeSPDX(t *testing.T) {
+	tests := map[string]string{
+		"mit":              "MIT",
+		"MIT":              "MIT",
+		"apache-2.0":       "Apache-2.0",
+		"Apache 2.0":       "Apache-2.0",
+		"bsd-3-clause":     "BSD-3-Clause",
+		"gpl-3.0-only":     "GPL-3.0-only",
+		"gpl-3.0-or-later": "GPL-3.0-or-later",
+		"GPL-2.0+":         "GPL-2.0-or-later",
+		"gpl-2.0":          "GPL-2.0-only",
+		"gpl-3.0":          "GPL-3.0-only",
+		"GPLv3":            "GPL-3.0-only",
+		"lgpl-2.1":         "LGPL-2.1-only",
+		"lgpl-3.0":         "LGPL-3.0-only",
+		"agpl-3.0":         "AGPL-3.0-only",
+		"cc0-1.0":          "CC0-1.0",
+		" zlib ":           "Zlib",
+		"custom-1.0":       "custom-1.0",
+		"":                 "",
+	}
+	for key, want := range tests {
+		if got := NormalizeSPDX(key); got != want {
+			t.Errorf("NormalizeSPDX(%q) = %q, want %q", key, got, want)
+		}
+	}
+	for key := range licenseColMap {
+		if _, ok := spdxIDs[key]; !ok && key != "other" {
+			t.Errorf("license %s has no SPDX identifier", key)
+		}
+	}
+	for key, l := range licenseCol {
+		if id := NormalizeSPDX(key); key != "other" && id != l.name {
+			t.Errorf("NormalizeSPDX(%q) = %q, want license name %q", key, id, l.name)
+		}
+	}
+}
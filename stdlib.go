@@ -0,0 +1,38 @@
+package glice
+
+import "strings"
+
+// stdlibModules are pseudo-modules of Go distribution itself
+var stdlibModules = map[string]bool{
+	"std":           true,
+	"cmd":           true,
+	toolchainModule: true,
+}
+
+// isStdlib reports whether module at path is part of Go project, either standard library
+// pseudo-module or one of golang.org/x/ subrepositories
+func isStdlib(path string) bool {
+	return stdlibModules[path] || strings.HasPrefix(path, "golang.org/x/")
+}
+
+// FilterStdlib returns parsed dependencies that are part of Go project, see Repository.IsStdlib
+func (c *Client) FilterStdlib() []*Repository {
+	var filtered []*Repository
+	for _, d := range c.dependencies {
+		if d.IsStdlib {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}
+
+// withoutStdlib returns repos that aren't part of Go project
+func withoutStdlib(repos []*Repository) []*Repository {
+	filtered := make([]*Repository, 0, len(repos))
+	for _, r := range repos {
+		if !r.IsStdlib {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
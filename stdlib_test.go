@@ -0,0 +1,71 @@
+package glice
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/mod/module"
+)
+
+func TestIsStdlib(t *testing.T) {
+	tests := map[string]struct {
+		path string
+		want bool
+	}{
+		"std":           {path: "std", want: true},
+		"cmd":           {path: "cmd", want: true},
+		"toolchain":     {path: "golang.org/toolchain", want: true},
+		"x module":      {path: "golang.org/x/crypto", want: true},
+		"x prefix only": {path: "golang.org/xyz"},
+		"third party":   {path: "github.com/fatih/color"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := isStdlib(tt.path); got != tt.want {
+				t.Errorf("isStdlib(%q) = %t, want %t", tt.path, got, tt.want)
+			}
+			r := getRepository(module.Version{Path: tt.path, Version: "v1.0.0"}, newRepoCache(), forgeHosts{})
+			if r.IsStdlib != tt.want {
+				t.Errorf("IsStdlib of %q = %t, want %t", tt.path, r.IsStdlib, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_FilterStdlib(t *testing.T) {
+	mod := &Repository{Name: "golang.org/x/mod", IsStdlib: true}
+	toolchain := &Repository{Name: "golang.org/toolchain", IsStdlib: true}
+	c := &Client{dependencies: []*Repository{mod, {Name: "github.com/fatih/color"}, toolchain}}
+	if got, want := c.FilterStdlib(), []*Repository{mod, toolchain}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterStdlib() = %+v, want %+v", got, want)
+	}
+	if len(c.dependencies) != 3 {
+		t.Errorf("FilterStdlib() modified dependencies: %+v", c.dependencies)
+	}
+}
+
+func TestClient_ParseDependenciesExcludeStdlib(t *testing.T) {
+	c, err := NewClient(wd(), WithExcludeStdlib())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseDependencies(false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range c.dependencies {
+		if d.IsStdlib {
+			t.Errorf("expected %s to be excluded", d.Name)
+		}
+	}
+	var want int
+	for _, d := range gliceDeps {
+		if !strings.HasPrefix(d, "golang.org/x/") {
+			want++
+		}
+	}
+	if len(c.dependencies) != want {
+		t.Errorf("expected %d dependencies, got %d", want, len(c.dependencies))
+	}
+}
@@ -0,0 +1,61 @@
+package glice
+
+import (
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// unknownLicense is used in summary for dependencies whose license couldn't be fetched
+const unknownLicense = "Unknown"
+
+// Summary returns number of dependencies per license. Dependencies skipped because their host is
+// unreachable are counted under UNREACHABLE.
+func (c *Client) Summary() map[string]int {
+	summary := map[string]int{}
+	for _, d := range c.dependencies {
+		l := d.License
+		if l == "" {
+			l = unknownLicense
+		}
+		summary[l]++
+	}
+	return summary
+}
+
+// spdxSummary returns number of dependencies per SPDX identifier of their license
+func (c *Client) spdxSummary() map[string]int {
+	summary := map[string]int{}
+	for _, d := range c.dependencies {
+		l := spdxLicense(d)
+		if l == spdxNoAssertion {
+			l = unknownLicense
+		}
+		summary[l]++
+	}
+	return summary
+}
+
+// printSummaryTable prints license counts, most used licenses first
+func (c *Client) printSummaryTable(writeTo io.Writer) {
+	summary := c.Summary()
+	licenses := make([]string, 0, len(summary))
+	for l := range summary {
+		licenses = append(licenses, l)
+	}
+	sort.Slice(licenses, func(i, j int) bool {
+		if summary[licenses[i]] != summary[licenses[j]] {
+			return summary[licenses[i]] > summary[licenses[j]]
+		}
+		return licenses[i] < licenses[j]
+	})
+
+	tw := tablewriter.NewWriter(writeTo)
+	tw.SetHeader([]string{"License", "Count"})
+	for _, l := range licenses {
+		tw.Append([]string{l, strconv.Itoa(summary[l])})
+	}
+	tw.Render()
+}
@@ -0,0 +1,81 @@
+package glice
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClient_Summary(t *testing.T) {
+	c := &Client{dependencies: []*Repository{
+		{Name: "github.com/ribice/glice", License: "MIT"},
+		{Name: "github.com/fatih/color", License: "MIT"},
+		{Name: "github.com/some/gpl", License: "GPL-3.0"},
+		{Name: "github.com/some/unknown"},
+	}}
+	want := map[string]int{"MIT": 2, "GPL-3.0": 1, "Unknown": 1}
+	if got := c.Summary(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Summary() = %v, want %v", got, want)
+	}
+}
+
+func TestClient_PrintSummary(t *testing.T) {
+	deps := []*Repository{
+		{Name: "github.com/ribice/glice", License: "MIT", Shortname: "MIT"},
+		{Name: "github.com/fatih/color", License: "MIT", Shortname: "MIT"},
+		{Name: "github.com/some/gpl", License: "GPL-3.0", Shortname: "GPL-3.0"},
+	}
+
+	c := &Client{format: "table", output: "stdout", dependencies: deps, summary: true}
+	output := &bytes.Buffer{}
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+	got := output.String()
+	if !strings.Contains(got, "COUNT") || strings.Index(got, "| MIT") > strings.Index(got, "| GPL-3.0") {
+		t.Errorf("expected summary table with most used licenses first, got:\n%s", got)
+	}
+
+	c.format = "json"
+	c.legacyJSON = true
+	output.Reset()
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Dependencies []*Repository  `json:"dependencies"`
+		Summary      map[string]int `json:"summary"`
+	}
+	if err := json.Unmarshal(output.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Dependencies) != 3 || !reflect.DeepEqual(doc.Summary, map[string]int{"MIT": 2, "GPL-3.0": 1}) {
+		t.Errorf("unexpected json summary: %+v", doc)
+	}
+}
+
+func TestClient_PrintJSONSummary(t *testing.T) {
+	c := &Client{format: "json", dependencies: []*Repository{
+		{Name: "github.com/fatih/color", License: "MIT", LicenseSPDX: "MIT"},
+		{Name: "github.com/ribice/glice", License: "MIT"},
+		{Name: "github.com/some/bsd", License: "BSD-3-Clause", LicenseSPDX: "BSD-3-Clause"},
+		{Name: "github.com/some/other", License: "Other"},
+	}}
+	output := &bytes.Buffer{}
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Modules []*Repository  `json:"modules"`
+		Summary map[string]int `json:"summary"`
+	}
+	if err := json.Unmarshal(output.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]int{"MIT": 2, "BSD-3-Clause": 1, unknownLicense: 1}; len(doc.Modules) != 4 || !reflect.DeepEqual(doc.Summary, want) {
+		t.Errorf("unexpected json output: %+v, want summary %v", doc, want)
+	}
+}
@@ -0,0 +1,154 @@
+package glice
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+)
+
+type tableColumn struct {
+	header string
+	// value returns cell of r, colored is set when output supports colors
+	value func(r *Repository, colored bool) string
+}
+
+var tableColumns = map[string]tableColumn{
+	"dependency": {header: "Dependency", value: func(r *Repository, _ bool) string { return r.Name }},
+	"url":        {header: "RepoURL", value: func(r *Repository, colored bool) string { return colorize(colored, color.FgBlue, r.URL) }},
+	"license":    {header: "License", value: licenseCell},
+	"version":    {header: "Version", value: func(r *Repository, _ bool) string { return r.Version }},
+	"host":       {header: "Host", value: func(r *Repository, _ bool) string { return hostCell(r) }},
+	"author":     {header: "Author", value: func(r *Repository, _ bool) string { return r.Author }},
+	"category":   {header: "Category", value: func(r *Repository, _ bool) string { return r.Category }},
+	"stars":      {header: "Stars", value: func(r *Repository, _ bool) string { return starsCell(r.Stars) }},
+	"sha":        {header: "SHA", value: func(r *Repository, _ bool) string { return shortSHA(r.CommitSHA) }},
+	"risk":       {header: "Risk", value: riskCell},
+	// risk_score is the same column as risk, named after json field
+	"risk_score": {header: "Risk", value: riskCell},
+	"vulns": {header: "Vulnerabilities", value: func(r *Repository, colored bool) string {
+		return colorize(colored, color.FgRed, strings.Join(r.Vulnerabilities, ", "))
+	}},
+}
+
+// licenseCell returns license of r, colored by license when colored is set
+func licenseCell(r *Repository, colored bool) string {
+	if r.License == "" {
+		return ""
+	}
+	return colorize(colored, getLicenseColor(r.License), r.License)
+}
+
+// hostCell returns hostname of Gitea or Forgejo instance instead of gitea
+func hostCell(r *Repository) string {
+	if r.Host == "gitea" {
+		return r.HostURL
+	}
+	return r.Host
+}
+
+func riskCell(r *Repository, _ bool) string {
+	return strconv.Itoa(r.RiskScore)
+}
+
+func starsCell(stars int) string {
+	if stars < 1 {
+		return ""
+	}
+	return strconv.Itoa(stars)
+}
+
+// shortSHA truncates git SHA to 8 characters
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
+// dryRunLicense is shown in license column of dependencies whose licenses weren't fetched with WithDryRun
+const dryRunLicense = "UNKNOWN"
+
+var defaultColumns = []string{"dependency", "url", "license", "version"}
+
+func tableColumnNames() []string {
+	names := make([]string, 0, len(tableColumns))
+	for name := range tableColumns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c *Client) printTable(ctx context.Context, writeTo io.Writer) error {
+	return c.renderTable(ctx, writeTo, c.dependencies)
+}
+
+// pagePrompt is printed after each page of table printed with WithPageSize
+const pagePrompt = "Press Enter for more..."
+
+// printTablePages prints table of dependencies in pages of pageSize rows, waiting for a line from
+// input before each next page. Printing stops when input ends.
+func (c *Client) printTablePages(ctx context.Context, writeTo io.Writer, input io.Reader) error {
+	in := bufio.NewReader(input)
+	for i := 0; i < len(c.dependencies); i += c.pageSize {
+		end := i + c.pageSize
+		if end > len(c.dependencies) {
+			end = len(c.dependencies)
+		}
+		if err := c.renderTable(ctx, writeTo, c.dependencies[i:end]); err != nil {
+			return err
+		}
+		if end == len(c.dependencies) {
+			break
+		}
+		if _, err := io.WriteString(writeTo, pagePrompt); err != nil {
+			return err
+		}
+		if _, err := in.ReadString('\n'); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// renderTable prints deps as a table with configured columns
+func (c *Client) renderTable(ctx context.Context, writeTo io.Writer, deps []*Repository) error {
+	cols := c.columns
+	if len(cols) < 1 {
+		cols = defaultColumns
+	}
+
+	header := make([]string, len(cols))
+	for i, col := range cols {
+		header[i] = tableColumns[col].header
+	}
+
+	colored := c.useColor(writeTo)
+	tw := tablewriter.NewWriter(writeTo)
+	tw.SetHeader(header)
+	for _, d := range deps {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		row := make([]string, len(cols))
+		for i, col := range cols {
+			row[i] = tableColumns[col].value(d, colored)
+			if c.dryRun && col == "license" && d.License == "" {
+				row[i] = dryRunLicense
+			}
+		}
+		tw.Append(row)
+	}
+	tw.Render()
+	return nil
+}
@@ -0,0 +1,128 @@
+package glice
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestClient_PrintTable(t *testing.T) {
+	deps := []*Repository{
+		{Name: "github.com/ribice/glice", License: "MIT", Shortname: "MIT", Category: CategoryPermissive, Version: "v1.0.0", CommitSHA: "0123456789abcdef", Stars: 1234, Host: "github.com", Author: "ribice", RiskScore: 1},
+		{Name: "codeberg.org/forgejo/kiss", Host: "gitea", HostURL: "codeberg.org", Author: "forgejo"},
+	}
+	tests := map[string]struct {
+		columns []string
+		want    []string
+		notWant []string
+	}{
+		"default columns": {
+			want:    []string{"DEPENDENCY", "REPOURL", "LICENSE", "VERSION", "v1.0.0"},
+			notWant: []string{"CATEGORY"},
+		},
+		"stars column": {
+			columns: []string{"dependency", "stars"},
+			want:    []string{"STARS", "1234"},
+		},
+		"sha column": {
+			columns: []string{"dependency", "sha"},
+			want:    []string{"SHA", "01234567"},
+			notWant: []string{"0123456789abcdef"},
+		},
+		"host and author columns": {
+			columns: []string{"dependency", "host", "author"},
+			want:    []string{"HOST", "AUTHOR", "| github.com   | ribice ", "| codeberg.org | forgejo "},
+			notWant: []string{"gitea", "REPOURL"},
+		},
+		"risk_score column": {
+			columns: []string{"dependency", "risk_score"},
+			want:    []string{"RISK", "|    1 |"},
+		},
+		"category column": {
+			columns: []string{"dependency", "category"},
+			want:    []string{"DEPENDENCY", "CATEGORY", "github.com/ribice/glice", CategoryPermissive},
+			notWant: []string{"VERSION", "v1.0.0"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Client{dependencies: deps, columns: tt.columns}
+			output := &bytes.Buffer{}
+			c.printTable(context.Background(), output)
+			got := output.String()
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("expected output to contain %q, got:\n%s", w, got)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(got, w) {
+					t.Errorf("expected output not to contain %q, got:\n%s", w, got)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_PrintTableColor(t *testing.T) {
+	deps := []*Repository{
+		{Name: "github.com/ribice/glice", URL: "https://github.com/ribice/glice", License: "MIT", Shortname: "MIT"},
+	}
+	// color is decided by destination writer when table is printed, not when licenses are fetched
+	for _, colored := range []bool{true, false} {
+		c := &Client{dependencies: deps}
+		WithColor(colored)(c)
+		output := &bytes.Buffer{}
+		c.printTable(context.Background(), output)
+		if got := strings.Contains(output.String(), "\x1b["); got != colored {
+			t.Errorf("WithColor(%t): output contains ANSI colors = %t:\n%s", colored, got, output.String())
+		}
+	}
+}
+
+func TestClient_PrintTablePages(t *testing.T) {
+	var deps []*Repository
+	for _, n := range []string{"a", "b", "c", "d", "e"} {
+		deps = append(deps, &Repository{Name: "example.com/" + n, License: "MIT"})
+	}
+	tests := map[string]struct {
+		input       string
+		wantTables  int
+		wantPrompts int
+		wantRows    int
+	}{
+		"all pages":  {input: "\n\n", wantTables: 3, wantPrompts: 2, wantRows: 5},
+		"input ends": {input: "\n", wantTables: 2, wantPrompts: 2, wantRows: 4},
+		"no input":   {wantTables: 1, wantPrompts: 1, wantRows: 2},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Client{dependencies: deps, pageSize: 2}
+			var buf bytes.Buffer
+			if err := c.printTablePages(context.Background(), &buf, strings.NewReader(tt.input)); err != nil {
+				t.Fatal(err)
+			}
+			got := buf.String()
+			if n := strings.Count(got, "DEPENDENCY"); n != tt.wantTables {
+				t.Errorf("expected %d tables, got %d:\n%s", tt.wantTables, n, got)
+			}
+			if n := strings.Count(got, pagePrompt); n != tt.wantPrompts {
+				t.Errorf("expected %d prompts, got %d:\n%s", tt.wantPrompts, n, got)
+			}
+			if n := strings.Count(got, "example.com/"); n != tt.wantRows {
+				t.Errorf("expected %d rows, got %d:\n%s", tt.wantRows, n, got)
+			}
+		})
+	}
+
+	// output that isn't a terminal isn't paged
+	c := &Client{dependencies: deps, pageSize: 2, format: "table"}
+	var buf bytes.Buffer
+	if err := c.PrintContext(context.Background(), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), pagePrompt) || strings.Count(buf.String(), "DEPENDENCY") != 1 {
+		t.Errorf("expected single table without prompts, got:\n%s", buf.String())
+	}
+}
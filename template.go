@@ -0,0 +1,34 @@
+package glice
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+)
+
+// DefaultTemplate renders the same columns as table format, separated by tabs
+const DefaultTemplate = `Dependency	RepoURL	License	Version
+{{range .}}{{.Name}}	{{.URL}}	{{.License}}	{{.Version}}
+{{end}}`
+
+func parseTemplate(tmpl string) (*template.Template, error) {
+	if tmpl == "" {
+		tmpl = DefaultTemplate
+	}
+	t, err := template.New("glice").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template provided: %w", err)
+	}
+	return t, nil
+}
+
+func (c *Client) printTemplate(writeTo io.Writer) error {
+	t := c.template
+	if t == nil {
+		var err error
+		if t, err = parseTemplate(c.templateText); err != nil {
+			return err
+		}
+	}
+	return t.Execute(writeTo, c.dependencies)
+}
@@ -0,0 +1,41 @@
+package glice
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewClient_Template(t *testing.T) {
+	if _, err := NewClient(wd(), WithFormat("template"), WithTemplate("{{range .}")); err == nil {
+		t.Error("expected error for invalid template")
+	}
+
+	c, err := NewClient(wd(), WithFormat("template"), WithTemplate("{{range .}}{{.Name}}={{.License}}\n{{end}}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.dependencies = []*Repository{{Name: "github.com/ribice/glice", License: "MIT"}, {Name: "golang.org/x/mod"}}
+
+	output := &bytes.Buffer{}
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+	if want := "github.com/ribice/glice=MIT\ngolang.org/x/mod=\n"; output.String() != want {
+		t.Errorf("Print() = %q, want %q", output.String(), want)
+	}
+}
+
+func TestClient_PrintDefaultTemplate(t *testing.T) {
+	c := &Client{format: "template", output: "stdout", dependencies: []*Repository{
+		{Name: "github.com/ribice/glice", URL: "https://github.com/ribice/glice", License: "MIT", Version: "v1.0.0"},
+	}}
+
+	output := &bytes.Buffer{}
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+	want := "Dependency\tRepoURL\tLicense\tVersion\ngithub.com/ribice/glice\thttps://github.com/ribice/glice\tMIT\tv1.0.0\n"
+	if output.String() != want {
+		t.Errorf("Print() = %q, want %q", output.String(), want)
+	}
+}
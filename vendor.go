@@ -0,0 +1,59 @@
+package glice
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/ribice/glice/v2/mod"
+)
+
+// vendorLicenseFiles are names of license files looked up in vendored modules, in order
+var vendorLicenseFiles = []string{"LICENSE", "LICENCE", "LICENSE.md", "LICENSE.txt", "COPYING"}
+
+// vendorPath returns vendor directory if vendoring is enabled and the directory exists
+func (c *Client) vendorPath() string {
+	if !c.vendor {
+		return ""
+	}
+	dir := c.vendorDir
+	if dir == "" {
+		dir = filepath.Join(c.path, "vendor")
+	}
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		return ""
+	}
+	return dir
+}
+
+// vendorReplacedPaths returns paths of modules replaced by dependencies, see mod.ReplacedPaths.
+// Modules are vendored under the path they are required as, not the path of their replacement.
+func (c *Client) vendorReplacedPaths() map[string]string {
+	paths, err := mod.ReplacedPaths(c.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		c.log().Warn("Could not read replace directives of vendored modules", "path", c.path, "error", err)
+	}
+	return paths
+}
+
+// vendorLicense sets license of r from license file of its vendored module in dir, returning false
+// if there is no readable license file. Replacements are looked up at path of module in replaced.
+func vendorLicense(dir string, r *Repository, replaced map[string]string) bool {
+	path := r.Name
+	if p, ok := replaced[r.Name]; ok {
+		path = p
+	}
+	for _, name := range vendorLicenseFiles {
+		bts, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path), name))
+		if err != nil {
+			continue
+		}
+
+		setLicense(r, classifyKey(string(bts)))
+		classifyLicense(r)
+		r.Text = base64.StdEncoding.EncodeToString(bts)
+		return true
+	}
+	return false
+}
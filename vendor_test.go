@@ -0,0 +1,95 @@
+package glice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVendorLicense(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"github.com/ribice/kiss/LICENSE.md": "Permission is hereby granted, free of charge, to any person obtaining a copy",
+		"github.com/some/custom/COPYING":    "All rights reserved.",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(p), 0755)
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[string]struct {
+		name        string
+		wantOK      bool
+		wantLicense string
+	}{
+		"detected license":    {name: "github.com/ribice/kiss", wantOK: true, wantLicense: "MIT"},
+		"unknown license":     {name: "github.com/some/custom", wantOK: true, wantLicense: "Other"},
+		"not vendored module": {name: "github.com/some/missing"},
+		"replacement":         {name: "github.com/fork/kiss", wantOK: true, wantLicense: "MIT"},
+	}
+	replaced := map[string]string{"github.com/fork/kiss": "github.com/ribice/kiss"}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &Repository{Name: tt.name}
+			if ok := vendorLicense(dir, r, replaced); ok != tt.wantOK {
+				t.Fatalf("vendorLicense() = %t, want %t", ok, tt.wantOK)
+			}
+			if r.License != tt.wantLicense {
+				t.Errorf("License = %s, want %s", r.License, tt.wantLicense)
+			}
+			if tt.wantOK && r.Text == "" {
+				t.Error("expected license text to be set")
+			}
+		})
+	}
+}
+
+func TestClient_ParseDependenciesVendorReplace(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                                "module example.com/a\n\ngo 1.18\n\nrequire github.com/ribice/kiss v1.0.0\n\nreplace github.com/ribice/kiss => github.com/fork/kiss v1.0.1\n",
+		"vendor/github.com/ribice/kiss/LICENSE": "Permission is hereby granted, free of charge, to any person obtaining a copy",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(p), 0755)
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// licenses of vendored modules aren't fetched, so any request fails
+	c, err := NewClient(dir, WithVendor(), WithHTTPClient(NewMockHTTPClient(nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseDependencies(false, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.dependencies) != 1 || c.dependencies[0].Name != "github.com/fork/kiss" || c.dependencies[0].License != "MIT" {
+		t.Errorf("expected replacement with license of vendored module, got %+v", c.dependencies)
+	}
+	if errs := c.Errors(); len(errs) != 0 {
+		t.Errorf("expected no fetching errors, got %v", errs)
+	}
+}
+
+func TestClient_VendorPath(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "vendor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := (&Client{path: dir}).vendorPath(); got != "" {
+		t.Errorf("expected vendor to be disabled by default, got %s", got)
+	}
+	if got := (&Client{path: dir, vendor: true}).vendorPath(); got != filepath.Join(dir, "vendor") {
+		t.Errorf("vendorPath() = %s, want default vendor directory", got)
+	}
+	if got := (&Client{path: dir, vendor: true, vendorDir: filepath.Join(dir, "missing")}).vendorPath(); got != "" {
+		t.Errorf("expected missing vendor directory to be ignored, got %s", got)
+	}
+}
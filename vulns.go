@@ -0,0 +1,131 @@
+package glice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// osvAPIURL is base URL of OSV.dev API
+var osvAPIURL = "https://api.osv.dev/v1"
+
+// osvBatchSize is maximum number of queries in a single OSV batch request
+const osvBatchSize = 1000
+
+// Vulnerability is a known vulnerability affecting version of a dependency, as reported by OSV.dev
+type Vulnerability struct {
+	PackageName string   `json:"package_name"`
+	Version     string   `json:"version"`
+	OSVID       string   `json:"osv_id"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+}
+
+type osvQuery struct {
+	Package osvPackage `json:"package"`
+	Version string     `json:"version"`
+}
+
+type osvPackage struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+type osvBatchResponse struct {
+	Results []struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	} `json:"results"`
+}
+
+type osvVuln struct {
+	ID      string   `json:"id"`
+	Summary string   `json:"summary"`
+	Aliases []string `json:"aliases"`
+}
+
+// CheckVulnerabilities looks up vulnerabilities affecting versions of parsed dependencies in OSV.dev
+// database. IDs of vulnerabilities are stored in Repository.Vulnerabilities as well, so they can be
+// shown in vulns table column. Dependencies without version are skipped.
+func (c *Client) CheckVulnerabilities(ctx context.Context) ([]*Vulnerability, error) {
+	var repos []*Repository
+	var queries []osvQuery
+	for _, d := range c.dependencies {
+		if d.Version == "" {
+			continue
+		}
+		repos = append(repos, d)
+		// OSV records Go module versions without v prefix
+		queries = append(queries, osvQuery{Package: osvPackage{Name: d.Name, Ecosystem: "Go"}, Version: strings.TrimPrefix(d.Version, "v")})
+	}
+
+	details := map[string]*osvVuln{}
+	var vulns []*Vulnerability
+	for i := 0; i < len(queries); i += osvBatchSize {
+		end := min(i+osvBatchSize, len(queries))
+		var resp osvBatchResponse
+		if err := c.osvRequest(ctx, http.MethodPost, "querybatch", map[string][]osvQuery{"queries": queries[i:end]}, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Results) != end-i {
+			return nil, fmt.Errorf("OSV returned %d results for %d queries", len(resp.Results), end-i)
+		}
+
+		for j, res := range resp.Results {
+			r := repos[i+j]
+			r.Vulnerabilities = nil
+			for _, v := range res.Vulns {
+				// batch API returns only IDs, details are fetched once per vulnerability
+				d, ok := details[v.ID]
+				if !ok {
+					d = &osvVuln{}
+					if err := c.osvRequest(ctx, http.MethodGet, "vulns/"+v.ID, nil, d); err != nil {
+						return nil, err
+					}
+					details[v.ID] = d
+				}
+				r.Vulnerabilities = append(r.Vulnerabilities, v.ID)
+				vulns = append(vulns, &Vulnerability{PackageName: r.Name, Version: r.Version, OSVID: v.ID, Aliases: d.Aliases, Summary: d.Summary})
+			}
+		}
+	}
+	return vulns, nil
+}
+
+// osvRequest sends body as JSON to OSV.dev API path and decodes response into out
+func (c *Client) osvRequest(ctx context.Context, method, path string, body, out interface{}) error {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return err
+		}
+	}
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, osvAPIURL+"/"+path, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	hc := c.httpClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: %s", method, req.URL, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
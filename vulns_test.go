@@ -0,0 +1,81 @@
+package glice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClient_CheckVulnerabilities(t *testing.T) {
+	var details int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/querybatch":
+			var body struct {
+				Queries []osvQuery `json:"queries"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			want := []osvQuery{
+				{Package: osvPackage{Name: "github.com/some/vulnerable", Ecosystem: "Go"}, Version: "1.0.0"},
+				{Package: osvPackage{Name: "github.com/ribice/glice", Ecosystem: "Go"}, Version: "2.0.0"},
+				{Package: osvPackage{Name: "github.com/some/other", Ecosystem: "Go"}, Version: "0.1.0"},
+			}
+			if !reflect.DeepEqual(body.Queries, want) {
+				t.Errorf("unexpected queries %+v", body.Queries)
+			}
+			fmt.Fprint(w, `{"results": [{"vulns": [{"id": "GO-2022-0001"}, {"id": "GO-2023-0002"}]}, {}, {"vulns": [{"id": "GO-2022-0001"}]}]}`)
+		case "/vulns/GO-2022-0001":
+			details++
+			fmt.Fprint(w, `{"id": "GO-2022-0001", "summary": "Denial of service", "aliases": ["CVE-2022-1234"]}`)
+		case "/vulns/GO-2023-0002":
+			details++
+			fmt.Fprint(w, `{"id": "GO-2023-0002", "summary": "Path traversal"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	defer func(u string) { osvAPIURL = u }(osvAPIURL)
+	osvAPIURL = srv.URL
+
+	vulnerable := &Repository{Name: "github.com/some/vulnerable", Version: "v1.0.0"}
+	c := &Client{dependencies: []*Repository{
+		vulnerable,
+		{Name: "github.com/ribice/glice", Version: "v2.0.0"},
+		{Name: "example.com/unversioned"},
+		{Name: "github.com/some/other", Version: "v0.1.0"},
+	}}
+	got, err := c.CheckVulnerabilities(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*Vulnerability{
+		{PackageName: "github.com/some/vulnerable", Version: "v1.0.0", OSVID: "GO-2022-0001", Aliases: []string{"CVE-2022-1234"}, Summary: "Denial of service"},
+		{PackageName: "github.com/some/vulnerable", Version: "v1.0.0", OSVID: "GO-2023-0002", Summary: "Path traversal"},
+		{PackageName: "github.com/some/other", Version: "v0.1.0", OSVID: "GO-2022-0001", Aliases: []string{"CVE-2022-1234"}, Summary: "Denial of service"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckVulnerabilities() = %+v, want %+v", got, want)
+	}
+	if details != 2 {
+		t.Errorf("expected details of each vulnerability to be fetched once, got %d requests", details)
+	}
+	if !reflect.DeepEqual(vulnerable.Vulnerabilities, []string{"GO-2022-0001", "GO-2023-0002"}) {
+		t.Errorf("unexpected vulnerabilities of dependency %v", vulnerable.Vulnerabilities)
+	}
+	if cell := tableColumns["vulns"].value(vulnerable, false); cell != "GO-2022-0001, GO-2023-0002" {
+		t.Errorf("unexpected vulns column %q", cell)
+	}
+
+	osvAPIURL = srv.URL + "/missing"
+	if _, err := c.CheckVulnerabilities(context.Background()); err == nil {
+		t.Error("expected error for failed OSV request")
+	}
+}
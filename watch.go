@@ -0,0 +1,88 @@
+package glice
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long Watch waits for go.mod to stop changing before dependencies are parsed again
+const watchDebounce = 500 * time.Millisecond
+
+// Watch parses and prints dependencies of go.mod at path to writeTo, and does so again each time go.mod
+// changes, with runs separated by a line with their timestamp. Changes are debounced, so rapid edits
+// result in a single run. Errors of runs are logged, as go.mod can be malformed mid-edit. Watch returns
+// nil once SIGINT or SIGTERM is received.
+func Watch(path string, indirect bool, writeTo io.Writer, opts ...Option) error {
+	c, err := NewClient(path, opts...)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return c.watch(ctx, indirect, writeTo, watchDebounce)
+}
+
+// watch runs parseAndPrint on start and after go.mod changes, until ctx is done
+func (c *Client) watch(ctx context.Context, indirect bool, writeTo io.Writer, debounce time.Duration) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	dir := c.path
+	if dir == "" {
+		dir = "."
+	}
+	// directory is watched instead of go.mod itself, as editors often replace files instead of writing to them
+	if err := watcher.Add(dir); err != nil {
+		return err
+	}
+
+	c.parseAndPrint(ctx, indirect, writeTo)
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Base(ev.Name) == "go.mod" && ev.Has(fsnotify.Write|fsnotify.Create) {
+				timer.Reset(debounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			c.log().Warn("Could not watch go.mod", "path", dir, "error", err)
+		case <-timer.C:
+			fmt.Fprintf(writeTo, "\n--- %s ---\n", time.Now().Format(time.RFC3339))
+			c.parseAndPrint(ctx, indirect, writeTo)
+		}
+	}
+}
+
+// parseAndPrint parses dependencies and prints them to writeTo, logging errors
+func (c *Client) parseAndPrint(ctx context.Context, indirect bool, writeTo io.Writer) {
+	// dependencies of previous run mustn't be printed if parsing modified go.mod fails
+	c.Reset()
+	if err := c.ParseDependencies(indirect, false); err != nil {
+		c.log().Error("Could not parse dependencies", "path", c.path, "error", err)
+		return
+	}
+	if err := c.PrintContext(ctx, writeTo); err != nil && ctx.Err() == nil {
+		c.log().Error("Could not print dependencies", "error", err)
+	}
+}
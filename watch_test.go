@@ -0,0 +1,84 @@
+package glice
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is bytes.Buffer safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestClient_Watch(t *testing.T) {
+	dir := t.TempDir()
+	goMod := filepath.Join(dir, "go.mod")
+	if err := os.WriteFile(goMod, []byte("module example.com/a\n\ngo 1.21\n\ntoolchain go1.21.3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewClient(dir, WithFormat("csv"), WithIncludeToolchain())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var out syncBuffer
+	done := make(chan error, 1)
+	go func() { done <- c.watch(ctx, false, &out, 50*time.Millisecond) }()
+
+	waitFor := func(runs int) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for strings.Count(out.String(), "Dependency,") < runs {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d runs, got output %q", runs, out.String())
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitFor(1)
+	// rapid edits are debounced into a single run
+	for i := 0; i < 3; i++ {
+		if err := os.WriteFile(goMod, []byte("module example.com/a\n\ngo 1.21\n\ntoolchain go1.22.0\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor(2)
+	time.Sleep(200 * time.Millisecond)
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("watch() error = %v", err)
+	}
+	got := out.String()
+	if n := strings.Count(got, "Dependency,"); n != 2 {
+		t.Errorf("expected 2 runs, got %d in %q", n, got)
+	}
+	if !strings.Contains(got, "golang.org/toolchain,https://go.googlesource.com/go,BSD-3-Clause,go1.22.0") {
+		t.Errorf("expected changed toolchain to be printed, got %q", got)
+	}
+	if !regexp.MustCompile(`\n--- \d{4}-\d{2}-\d{2}T\S+ ---\n`).MatchString(got) {
+		t.Errorf("expected separator with timestamp, got %q", got)
+	}
+}
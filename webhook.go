@@ -0,0 +1,88 @@
+package glice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// webhookEvents are events that can be sent to webhook set with WithWebhook
+var webhookEvents = map[string]bool{
+	// violation is sent when dependencies use blocked or unknown license
+	"violation": true,
+}
+
+// webhookPayload is JSON body posted to webhook. Text summarizes violations, so it can be
+// shown by Slack incoming webhooks as is.
+type webhookPayload struct {
+	Event      string             `json:"event"`
+	Text       string             `json:"text"`
+	Violations []webhookViolation `json:"violations"`
+}
+
+type webhookViolation struct {
+	Module string `json:"module"`
+	// Type is either blocked or unknown
+	Type      string    `json:"type"`
+	License   string    `json:"license"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// violations returns dependencies using license from BlockedLicenses and ones with unknown license
+func (c *Client) violations(now time.Time) []webhookViolation {
+	var vs []webhookViolation
+	blocked, _ := c.CheckBlocked()
+	for _, d := range blocked {
+		vs = append(vs, webhookViolation{Module: d.Name, Type: "blocked", License: d.License, Timestamp: now})
+	}
+	for _, d := range c.FilterUnknown() {
+		vs = append(vs, webhookViolation{Module: d.Name, Type: "unknown", License: d.License, Timestamp: now})
+	}
+	return vs
+}
+
+// notifyWebhook posts violations of parsed dependencies to webhook set with WithWebhook, if it
+// is subscribed to violation event
+func (c *Client) notifyWebhook() error {
+	if c.webhookURL == "" || !containsFold(c.webhookEvents, "violation") {
+		return nil
+	}
+	vs := c.violations(time.Now().UTC())
+	if len(vs) < 1 {
+		return nil
+	}
+
+	bts, err := json.Marshal(webhookPayload{
+		Event:      "violation",
+		Text:       fmt.Sprintf("glice found %d license violations in %s", len(vs), c.moduleName()),
+		Violations: vs,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.webhookURL, bytes.NewReader(bts))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	hc := c.httpClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
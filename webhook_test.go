@@ -0,0 +1,73 @@
+package glice
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_NotifyWebhook(t *testing.T) {
+	var got []webhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p webhookPayload
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s with content type %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Error(err)
+		}
+		got = append(got, p)
+	}))
+	defer srv.Close()
+
+	deps := []*Repository{
+		{Name: "github.com/fatih/color", License: "MIT"},
+		{Name: "github.com/some/gpl", License: "GPL-3.0"},
+		{Name: "github.com/some/other", License: "Other"},
+	}
+	tests := map[string]struct {
+		deps      []*Repository
+		wantTypes []string
+	}{
+		"violations":    {deps: deps, wantTypes: []string{"blocked", "unknown"}},
+		"no violations": {deps: deps[:1]},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got = nil
+			c := &Client{BlockedLicenses: []string{"GPL-3.0"}, dependencies: tt.deps, timeout: time.Second}
+			WithWebhook(srv.URL)(c)
+			if err := c.notifyWebhook(); err != nil {
+				t.Fatal(err)
+			}
+			if len(tt.wantTypes) < 1 {
+				if len(got) != 0 {
+					t.Errorf("expected no webhook calls, got %v", got)
+				}
+				return
+			}
+			if len(got) != 1 || got[0].Event != "violation" || len(got[0].Violations) != len(tt.wantTypes) {
+				t.Fatalf("unexpected payloads %+v", got)
+			}
+			for i, v := range got[0].Violations {
+				if v.Type != tt.wantTypes[i] || v.Timestamp.IsZero() {
+					t.Errorf("unexpected violation %+v, want type %s", v, tt.wantTypes[i])
+				}
+			}
+			if v := got[0].Violations[0]; v.Module != "github.com/some/gpl" || v.License != "GPL-3.0" {
+				t.Errorf("unexpected blocked violation %+v", v)
+			}
+		})
+	}
+
+	c := &Client{dependencies: deps, timeout: time.Second}
+	WithWebhook("http://127.0.0.1:0")(c)
+	if err := c.notifyWebhook(); err == nil {
+		t.Error("expected error for unreachable webhook")
+	}
+	if _, err := NewClient(wd(), WithWebhook(srv.URL, "fetched")); err == nil {
+		t.Error("expected error for invalid webhook event")
+	}
+}
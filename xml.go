@@ -0,0 +1,26 @@
+package glice
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+type xmlDependencies struct {
+	XMLName      xml.Name      `xml:"dependencies"`
+	Dependencies []*Repository `xml:"dependency"`
+}
+
+func (c *Client) printXML(writeTo io.Writer) error {
+	if _, err := io.WriteString(writeTo, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(writeTo)
+	enc.Indent("", "  ")
+	if err := enc.Encode(xmlDependencies{Dependencies: c.dependencies}); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(writeTo, "\n")
+	return err
+}
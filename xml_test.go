@@ -0,0 +1,37 @@
+package glice
+
+import (
+	"bytes"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClient_PrintXML(t *testing.T) {
+	deps := []*Repository{
+		{Name: "github.com/ribice/glice", URL: "https://github.com/ribice/glice", Host: "github.com", Author: "ribice", Project: "glice", License: "MIT", Version: "v1.0.0"},
+		{Name: "golang.org/x/mod"},
+	}
+	c := &Client{format: "xml", output: "stdout", dependencies: deps}
+
+	output := &bytes.Buffer{}
+	if err := c.Print(output); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(output.String(), `<?xml version="1.0" encoding="UTF-8"?>`) {
+		t.Error("expected XML declaration")
+	}
+	if strings.Contains(output.String(), "<license></license>") {
+		t.Error("expected empty fields to be omitted")
+	}
+
+	var got xmlDependencies
+	if err := xml.Unmarshal(output.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Dependencies, deps) {
+		t.Errorf("round trip = %v, want %v", got.Dependencies, deps)
+	}
+}